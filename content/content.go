@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/goccy/go-yaml"
 	"github.com/goccy/go-yaml/ast"
 	"github.com/goccy/go-yaml/parser"
@@ -24,8 +25,12 @@ const (
 	FileTypeYAML = "yaml"
 	// FileTypeJSON identifies JSON content.
 	FileTypeJSON = "json"
+	// FileTypeXML identifies XML content.
+	FileTypeXML = "xml"
 	// FileTypeCSV identifies CSV content.
 	FileTypeCSV = "csv"
+	// FileTypeTOML identifies TOML content.
+	FileTypeTOML = "toml"
 	// FileTypeString identifies string content.
 	FileTypeString = "string"
 	// FileTypeUnknown identifies content that could not be classified.
@@ -138,6 +143,15 @@ func IsCSV(content string) bool {
 	return len(records[0]) > 1
 }
 
+// IsTOML checks if the passed content of TOML, with at least one key.
+func IsTOML(content string) bool {
+	var tml map[string]any
+
+	metadata, err := toml.Decode(content, &tml)
+
+	return err == nil && len(metadata.Keys()) != 0
+}
+
 func normalizeContent(content string) string {
 	normalized := ansiEscapePattern.ReplaceAllString(content, "")
 	normalized = strings.TrimPrefix(normalized, "\ufeff")
@@ -145,9 +159,9 @@ func normalizeContent(content string) string {
 	return strings.Trim(normalized, "\r\n")
 }
 
-// CheckFileType checks the file type of the content passed, it validates for YAML/JSON.
+// CheckFileType checks the file type of the content passed, it validates for YAML/JSON/XML/CSV/TOML.
 func (obj Object) CheckFileType(log *logrus.Logger) string {
-	log.Debug("identifying the input file type, allowed types are YAML/JSON/CSV")
+	log.Debug("identifying the input file type, allowed types are YAML/JSON/XML/CSV/TOML")
 
 	content := normalizeContent(string(obj))
 
@@ -157,6 +171,12 @@ func (obj Object) CheckFileType(log *logrus.Logger) string {
 		return FileTypeJSON
 	}
 
+	if IsXML(content) {
+		log.Debug("input file type identified as XML")
+
+		return FileTypeXML
+	}
+
 	if IsCSV(content) {
 		log.Debug("input file type identified as CSV")
 
@@ -169,6 +189,12 @@ func (obj Object) CheckFileType(log *logrus.Logger) string {
 		return FileTypeYAML
 	}
 
+	if IsTOML(content) {
+		log.Debug("input file type identified as TOML")
+
+		return FileTypeTOML
+	}
+
 	if IsJSONString(content) || IsYAMLString(log, content) {
 		log.Debug("input file type identified as string")
 
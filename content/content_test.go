@@ -25,6 +25,13 @@ func init() {
 }
 
 func TestObject_CheckFileType(t *testing.T) {
+	t.Run("should validate content as toml", func(t *testing.T) {
+		obj := content.Object("title = \"sample\"\n\n[owner]\nname = \"gocd\"\n")
+
+		actual := obj.CheckFileType(log)
+		assert.Equal(t, content.FileTypeTOML, actual)
+	})
+
 	t.Run("should validate content as json", func(t *testing.T) {
 		obj := content.Object(`{"name": "testing"}`)
 
@@ -131,6 +138,24 @@ pipelines:
 		assert.Equal(t, "unknown", actual)
 	})
 
+	t.Run("should validate content as xml", func(t *testing.T) {
+		fileData, err := os.ReadFile("../fixtures/sample.xml")
+		require.NoError(t, err)
+
+		obj := content.Object(fileData)
+		actual := obj.CheckFileType(log)
+		assert.Equal(t, "xml", actual)
+	})
+
+	t.Run("should validate content as unknown since malformed xml passed", func(t *testing.T) {
+		fileData, err := os.ReadFile("../fixtures/sample_faulty.xml")
+		require.NoError(t, err)
+
+		obj := content.Object(fileData)
+		actual := obj.CheckFileType(log)
+		assert.NotEqual(t, "xml", actual)
+	})
+
 	t.Run("should validate content as csv", func(t *testing.T) {
 		fileData, err := os.ReadFile("../fixtures/sample.csv")
 		require.NoError(t, err)
@@ -0,0 +1,410 @@
+package content
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/goccy/go-yaml"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// tomlPathSeparator joins the keys of TOML tables while identifying their order, it cannot be part of a key.
+const tomlPathSeparator = "\x00"
+
+// Convert converts the content to the target format, one of FileTypeYAML, FileTypeJSON, FileTypeTOML or FileTypeCSV.
+// The source format is identified with CheckFileType and the order of keys is preserved for all the formats except TOML output,
+// which is written with sorted keys. Conversions that would lose data fail with an error instead,
+// such as nested values or lists without objects to CSV, and null values or content without a table at the root to TOML.
+func (obj Object) Convert(log *logrus.Logger, target string) (converted Object, err error) {
+	source := obj.CheckFileType(log)
+
+	log.Debugf("converting content from '%s' to '%s'", source, target)
+
+	// github.com/goccy/go-yaml can produce panics, the content is reported as not converted when it does.
+	err = &errors.CommonError{Message: fmt.Sprintf("converting content from '%s' to '%s' failed", source, target)}
+	defer handlePanic(log)
+
+	value, parseErr := parseOrdered(normalizeContent(string(obj)), source)
+	if parseErr != nil {
+		return "", parseErr
+	}
+
+	return encodeOrdered(value, target)
+}
+
+// encodeOrdered encodes the value parsed by parseOrdered to the target format.
+func encodeOrdered(value any, target string) (Object, error) {
+	var (
+		out []byte
+		err error
+	)
+
+	switch strings.ToLower(target) {
+	case FileTypeYAML:
+		out, err = EncodeOptions{NoDocumentSeparator: true}.EncodeYAML(value)
+	case FileTypeJSON:
+		out, err = encodeOrderedJSON(value)
+	case FileTypeTOML:
+		out, err = encodeTOML(value)
+	case FileTypeCSV:
+		out, err = encodeCSV(value)
+	default:
+		return "", &errors.CommonError{
+			Message: fmt.Sprintf("unsupported target format '%s', should be one of '%s', '%s', '%s' or '%s'",
+				target, FileTypeYAML, FileTypeJSON, FileTypeTOML, FileTypeCSV),
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return Object(out), nil
+}
+
+// parseOrdered parses the content to generic values, where the objects are parsed as yaml.MapSlice to retain the order of keys.
+func parseOrdered(content, source string) (any, error) {
+	var value any
+
+	switch source {
+	case FileTypeJSON, FileTypeYAML:
+		if err := yaml.UnmarshalWithOptions([]byte(content), &value, yaml.UseOrderedMap()); err != nil {
+			return nil, &errors.CommonError{Message: fmt.Sprintf("parsing %s content errored with '%v'", source, err)}
+		}
+	case FileTypeTOML:
+		var table map[string]any
+
+		metadata, err := toml.Decode(content, &table)
+		if err != nil {
+			return nil, &errors.CommonError{Message: fmt.Sprintf("parsing toml content errored with '%v'", err)}
+		}
+
+		value = orderedTOML(table, "", tomlKeyOrder(metadata))
+	case FileTypeCSV:
+		return parseCSV(content)
+	default:
+		return nil, &errors.CommonError{Message: fmt.Sprintf("cannot convert content of type '%s', only YAML, JSON, TOML and CSV are supported", source)}
+	}
+
+	return value, nil
+}
+
+// tomlKeyOrder returns the keys of every table in the order they appear in the document, indexed by the path of the table.
+func tomlKeyOrder(metadata toml.MetaData) map[string][]string {
+	order := make(map[string][]string)
+	seen := make(map[string]bool)
+
+	for _, key := range metadata.Keys() {
+		path := strings.Join(key, tomlPathSeparator)
+		if seen[path] {
+			continue
+		}
+
+		seen[path] = true
+		parent := strings.Join(key[:len(key)-1], tomlPathSeparator)
+		order[parent] = append(order[parent], key[len(key)-1])
+	}
+
+	return order
+}
+
+func orderedTOML(value any, path string, order map[string][]string) any {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		ordered := make(yaml.MapSlice, 0, len(typedValue))
+		added := make(map[string]bool, len(typedValue))
+
+		for _, key := range slices.Concat(order[path], sortedStringKeys(typedValue)) {
+			element, ok := typedValue[key]
+			if !ok || added[key] {
+				continue
+			}
+
+			added[key] = true
+			ordered = append(ordered, yaml.MapItem{Key: key, Value: orderedTOML(element, tomlChildPath(path, key), order)})
+		}
+
+		return ordered
+	case []map[string]any:
+		ordered := make([]any, 0, len(typedValue))
+		for _, element := range typedValue {
+			ordered = append(ordered, orderedTOML(element, path, order))
+		}
+
+		return ordered
+	case []any:
+		ordered := make([]any, 0, len(typedValue))
+		for _, element := range typedValue {
+			ordered = append(ordered, orderedTOML(element, path, order))
+		}
+
+		return ordered
+	case time.Time:
+		return tomlTime(typedValue)
+	default:
+		return value
+	}
+}
+
+// tomlTime formats the TOML date-times as strings, local dates and times without the offset.
+func tomlTime(value time.Time) string {
+	switch value.Location().String() {
+	case "date-local":
+		return value.Format(time.DateOnly)
+	case "time-local":
+		return value.Format("15:04:05.999999999")
+	case "datetime-local":
+		return value.Format("2006-01-02T15:04:05.999999999")
+	default:
+		return value.Format(time.RFC3339Nano)
+	}
+}
+
+func tomlChildPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + tomlPathSeparator + key
+}
+
+// parseCSV parses the CSV content as a list of objects, with the header row as keys.
+func parseCSV(content string) (any, error) {
+	records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("parsing csv content errored with '%v'", err)}
+	}
+
+	rows := make([]any, 0, len(records))
+
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	headers := records[0]
+
+	for _, record := range records[1:] {
+		row := make(yaml.MapSlice, 0, len(headers))
+		for index, header := range headers {
+			row = append(row, yaml.MapItem{Key: header, Value: record[index]})
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// encodeOrderedJSON encodes the value to JSON, retaining the order of keys of yaml.MapSlice.
+func encodeOrderedJSON(value any) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := writeOrderedJSON(&compact, value); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", strings.Repeat(" ", defaultJSONIndent)); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func writeOrderedJSON(out *bytes.Buffer, value any) error {
+	switch typedValue := value.(type) {
+	case yaml.MapSlice:
+		out.WriteByte('{')
+
+		for index, item := range typedValue {
+			if index != 0 {
+				out.WriteByte(',')
+			}
+
+			key, err := json.Marshal(fmt.Sprint(item.Key))
+			if err != nil {
+				return err
+			}
+
+			out.Write(key)
+			out.WriteByte(':')
+
+			if err = writeOrderedJSON(out, item.Value); err != nil {
+				return err
+			}
+		}
+
+		out.WriteByte('}')
+	case []any:
+		out.WriteByte('[')
+
+		for index, element := range typedValue {
+			if index != 0 {
+				out.WriteByte(',')
+			}
+
+			if err := writeOrderedJSON(out, element); err != nil {
+				return err
+			}
+		}
+
+		out.WriteByte(']')
+	default:
+		scalar, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		out.Write(scalar)
+	}
+
+	return nil
+}
+
+// encodeTOML encodes the value to TOML, which requires a table at the root and does not support null values.
+func encodeTOML(value any) ([]byte, error) {
+	if _, ok := value.(yaml.MapSlice); !ok {
+		return nil, &errors.CommonError{Message: "cannot convert to toml, toml requires a table at the root of the content"}
+	}
+
+	table, err := unorderedValue(value, "$")
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodeOptions{}.EncodeTOML(table)
+}
+
+// unorderedValue converts yaml.MapSlice to maps, as expected by the TOML encoder.
+func unorderedValue(value any, path string) (any, error) {
+	switch typedValue := value.(type) {
+	case nil:
+		return nil, &errors.CommonError{Message: fmt.Sprintf("cannot convert to toml, toml does not support null value at '%s'", path)}
+	case yaml.MapSlice:
+		table := make(map[string]any, len(typedValue))
+
+		for _, item := range typedValue {
+			key := fmt.Sprint(item.Key)
+
+			element, err := unorderedValue(item.Value, path+"."+key)
+			if err != nil {
+				return nil, err
+			}
+
+			table[key] = element
+		}
+
+		return table, nil
+	case []any:
+		list := make([]any, 0, len(typedValue))
+
+		for index, element := range typedValue {
+			converted, err := unorderedValue(element, fmt.Sprintf("%s[%d]", path, index))
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, converted)
+		}
+
+		return list, nil
+	default:
+		return value, nil
+	}
+}
+
+// encodeCSV encodes a list of flat objects, or a single flat object, to CSV with the union of keys as headers in the order they appear.
+func encodeCSV(value any) ([]byte, error) {
+	var rows []any
+
+	switch typedValue := value.(type) {
+	case []any:
+		rows = typedValue
+	case yaml.MapSlice:
+		rows = []any{typedValue}
+	default:
+		return nil, &errors.CommonError{Message: "cannot convert to csv, csv supports only a list of objects or an object"}
+	}
+
+	headers := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for index, row := range rows {
+		object, ok := row.(yaml.MapSlice)
+		if !ok {
+			return nil, &errors.CommonError{Message: fmt.Sprintf("cannot convert to csv, value at '$[%d]' is not an object", index)}
+		}
+
+		for _, item := range object {
+			key := fmt.Sprint(item.Key)
+
+			switch item.Value.(type) {
+			case yaml.MapSlice, []any:
+				return nil, &errors.CommonError{Message: fmt.Sprintf("cannot convert to csv, nested value at '$[%d].%s' cannot be represented in csv", index, key)}
+			}
+
+			if !seen[key] {
+				seen[key] = true
+				headers = append(headers, key)
+			}
+		}
+	}
+
+	lines := make([][]string, 0, len(rows)+1)
+	lines = append(lines, headers)
+
+	for _, row := range rows {
+		cells := make(map[string]string)
+		for _, item := range row.(yaml.MapSlice) {
+			cells[fmt.Sprint(item.Key)] = csvCell(item.Value)
+		}
+
+		line := make([]string, 0, len(headers))
+		for _, header := range headers {
+			line = append(line, cells[header])
+		}
+
+		lines = append(lines, line)
+	}
+
+	var out bytes.Buffer
+
+	csvWriter := csv.NewWriter(&out)
+	if err := csvWriter.WriteAll(lines); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func csvCell(value any) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typedValue
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64)
+	default:
+		return fmt.Sprint(typedValue)
+	}
+}
+
+func sortedStringKeys(table map[string]any) []string {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
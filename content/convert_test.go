@@ -0,0 +1,93 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObject_Convert(t *testing.T) {
+	t.Run("should convert yaml to json retaining the order of keys", func(t *testing.T) {
+		obj := content.Object("name: sample\nversion: 2\nlabels:\n  tier: backend\n  app: api\nports:\n  - 80\n  - 443\n")
+
+		actual, err := obj.Convert(log, content.FileTypeJSON)
+		require.NoError(t, err)
+		assert.Equal(t, `{
+     "name": "sample",
+     "version": 2,
+     "labels": {
+          "tier": "backend",
+          "app": "api"
+     },
+     "ports": [
+          80,
+          443
+     ]
+}`, actual.String())
+	})
+
+	t.Run("should convert json to yaml retaining the order of keys", func(t *testing.T) {
+		obj := content.Object(`{"name": "sample", "enabled": true, "args": ["--debug"], "spec": {"replicas": 2, "image": "api:v1"}}`)
+
+		actual, err := obj.Convert(log, content.FileTypeYAML)
+		require.NoError(t, err)
+		assert.Equal(t, "name: sample\nenabled: true\nargs:\n  - --debug\nspec:\n  replicas: 2\n  image: api:v1\n", actual.String())
+	})
+
+	t.Run("should convert toml to yaml retaining the order of keys", func(t *testing.T) {
+		obj := content.Object("title = \"sample\"\nport = 8080\n\n[owner]\nname = \"gocd\"\nborn = 1979-05-27\n\n[[stages]]\nname = \"build\"\n\n[[stages]]\nname = \"test\"\n")
+
+		actual, err := obj.Convert(log, content.FileTypeYAML)
+		require.NoError(t, err)
+		assert.Equal(t, "title: sample\nport: 8080\nowner:\n  name: gocd\n  born: \"1979-05-27\"\nstages:\n  - name: build\n  - name: test\n", actual.String())
+	})
+
+	t.Run("should convert yaml to toml", func(t *testing.T) {
+		obj := content.Object("name: sample\nspec:\n  replicas: 2\n")
+
+		actual, err := obj.Convert(log, content.FileTypeTOML)
+		require.NoError(t, err)
+		assert.Equal(t, "name = \"sample\"\n\n[spec]\n  replicas = 2\n", actual.String())
+	})
+
+	t.Run("should convert csv to json and back", func(t *testing.T) {
+		obj := content.Object("name,status\nbuild,Passed\ndeploy,Failed\n")
+
+		actual, err := obj.Convert(log, content.FileTypeJSON)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name":"build","status":"Passed"},{"name":"deploy","status":"Failed"}]`, actual.String())
+
+		csv, err := actual.Convert(log, content.FileTypeCSV)
+		require.NoError(t, err)
+		assert.Equal(t, obj.String(), csv.String())
+	})
+
+	t.Run("should convert list of objects to csv with union of keys", func(t *testing.T) {
+		obj := content.Object("- name: build\n  counter: 10\n- name: deploy\n  passed: false\n")
+
+		actual, err := obj.Convert(log, content.FileTypeCSV)
+		require.NoError(t, err)
+		assert.Equal(t, "name,counter,passed\nbuild,10,\ndeploy,,false\n", actual.String())
+	})
+
+	t.Run("should fail for lossy conversions", func(t *testing.T) {
+		_, err := content.Object("- name: build\n  stages:\n    - compile\n").Convert(log, content.FileTypeCSV)
+		require.EqualError(t, err, "cannot convert to csv, nested value at '$[0].stages' cannot be represented in csv")
+
+		_, err = content.Object("name: build\nowner: null\n").Convert(log, content.FileTypeTOML)
+		require.EqualError(t, err, "cannot convert to toml, toml does not support null value at '$.owner'")
+
+		_, err = content.Object(`["build", "deploy"]`).Convert(log, content.FileTypeTOML)
+		require.EqualError(t, err, "cannot convert to toml, toml requires a table at the root of the content")
+	})
+
+	t.Run("should fail for unsupported formats", func(t *testing.T) {
+		_, err := content.Object("name: build\n").Convert(log, "ini")
+		require.EqualError(t, err, "unsupported target format 'ini', should be one of 'yaml', 'json', 'toml' or 'csv'")
+
+		_, err = content.Object("<root><name>build</name></root>").Convert(log, content.FileTypeJSON)
+		require.EqualError(t, err, "cannot convert content of type 'xml', only YAML, JSON, TOML and CSV are supported")
+	})
+}
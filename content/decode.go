@@ -0,0 +1,142 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DecodeOption customises how Decode decodes the content.
+type DecodeOption func(config *decodeConfig)
+
+type decodeConfig struct {
+	log                *logrus.Logger
+	strict             bool
+	allowDuplicateKeys bool
+}
+
+// WithStrict fails the decoding when the content has fields that are not part of the value decoded into.
+func WithStrict() DecodeOption {
+	return func(config *decodeConfig) {
+		config.strict = true
+	}
+}
+
+// WithDuplicateKeys allows the keys repeated in a mapping, where the last one wins. Duplicate keys fail the decoding by default.
+func WithDuplicateKeys() DecodeOption {
+	return func(config *decodeConfig) {
+		config.allowDuplicateKeys = true
+	}
+}
+
+// WithLogger sets the logger used while identifying the type of the content.
+func WithLogger(log *logrus.Logger) DecodeOption {
+	return func(config *decodeConfig) {
+		config.log = log
+	}
+}
+
+// Decode identifies the type of the content and decodes it into the value v, which should be a pointer.
+// JSON content is decoded with encoding/json, so the fields are matched by their json tags. YAML, TOML and CSV content is decoded
+// the same way as YAML, so the fields are matched by their yaml tags, falling back to the json tags, and the types implementing
+// json.Unmarshaler or yaml.BytesUnmarshaler decode themselves. CSV content is decoded as a list of objects with the header row as keys.
+func (obj Object) Decode(v any, opts ...DecodeOption) (err error) {
+	config := &decodeConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.log == nil {
+		config.log = logrus.New()
+	}
+
+	content := normalizeContent(string(obj))
+	fileType := obj.CheckFileType(config.log)
+
+	// github.com/goccy/go-yaml can produce panics, the content is reported as not decoded when it does.
+	err = &errors.CommonError{Message: fmt.Sprintf("decoding %s content failed", fileType)}
+	defer handlePanic(config.log)
+
+	data := []byte(content)
+
+	switch fileType {
+	case FileTypeJSON:
+		return decodeJSON(data, v, config)
+	case FileTypeYAML, FileTypeString:
+	case FileTypeTOML, FileTypeCSV:
+		value, parseErr := parseOrdered(content, fileType)
+		if parseErr != nil {
+			return parseErr
+		}
+
+		var marshalErr error
+		if data, marshalErr = yaml.Marshal(value); marshalErr != nil {
+			return &errors.CommonError{Message: fmt.Sprintf("decoding %s content errored with '%v'", fileType, marshalErr)}
+		}
+	default:
+		return &errors.CommonError{Message: fmt.Sprintf("cannot decode content of type '%s', only YAML, JSON, TOML and CSV are supported", fileType)}
+	}
+
+	decodeOptions := []yaml.DecodeOption{yaml.UseJSONUnmarshaler()}
+	if config.strict {
+		decodeOptions = append(decodeOptions, yaml.DisallowUnknownField())
+	}
+
+	if config.allowDuplicateKeys {
+		decodeOptions = append(decodeOptions, yaml.AllowDuplicateMapKey())
+	}
+
+	if decodeErr := yaml.UnmarshalWithOptions(data, v, decodeOptions...); decodeErr != nil {
+		return decodeError(fileType, decodeErr)
+	}
+
+	return nil
+}
+
+// decodeJSON decodes the JSON content with encoding/json, failing on duplicate keys unless they are allowed
+// and on unknown fields in strict mode.
+func decodeJSON(data []byte, v any, config *decodeConfig) error {
+	if !config.allowDuplicateKeys {
+		// encoding/json lets the last of the duplicate keys win, they are identified by parsing the content as YAML.
+		if _, err := parser.ParseBytes(data, 0); err != nil {
+			return decodeError(FileTypeJSON, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if config.strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(v); err != nil {
+		return &errors.CommonError{Message: fmt.Sprintf("decoding %s content errored: %s", FileTypeJSON, strings.TrimPrefix(err.Error(), "json: "))}
+	}
+
+	return nil
+}
+
+// decodeError returns the error with the position of the offending token, for the content that is decoded as it is.
+// The position is left out for TOML and CSV, since they are converted before decoding.
+func decodeError(fileType string, err error) error {
+	var yamlError yaml.Error
+	if !stdErrors.As(err, &yamlError) {
+		return &errors.CommonError{Message: fmt.Sprintf("decoding %s content errored with '%v'", fileType, err)}
+	}
+
+	token := yamlError.GetToken()
+	if fileType == FileTypeTOML || fileType == FileTypeCSV || token == nil || token.Position == nil {
+		return &errors.CommonError{Message: fmt.Sprintf("decoding %s content errored: %s", fileType, yamlError.GetMessage())}
+	}
+
+	return &errors.CommonError{
+		Message: fmt.Sprintf("decoding %s content errored at line %d, column %d: %s",
+			fileType, token.Position.Line, token.Position.Column, yamlError.GetMessage()),
+	}
+}
@@ -0,0 +1,117 @@
+package content_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodePipeline struct {
+	Name    string        `json:"name"`
+	Stages  []string      `json:"stages,omitempty"`
+	Retries int           `json:"retries,omitempty" yaml:"max_retries,omitempty"`
+	Owner   decodeOwner   `json:"owner,omitempty"`
+	Timeout decodeTimeout `json:"timeout,omitempty"`
+}
+
+type decodeOwner struct {
+	Team string `json:"team"`
+}
+
+// decodeTimeout decodes itself from JSON, accepting the duration with or without the unit.
+type decodeTimeout string
+
+func (timeout *decodeTimeout) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*timeout = decodeTimeout(strings.TrimSuffix(value, "s") + "s")
+
+	return nil
+}
+
+func TestObject_Decode(t *testing.T) {
+	expected := decodePipeline{Name: "sample", Stages: []string{"build", "test"}, Retries: 2, Owner: decodeOwner{Team: "core"}, Timeout: "30s"}
+
+	t.Run("should decode yaml by the yaml tags and json by the json tags", func(t *testing.T) {
+		for _, obj := range []content.Object{
+			"name: sample\nstages: [build, test]\nmax_retries: 2\nowner:\n  team: core\ntimeout: \"30\"\n",
+			`{"name": "sample", "stages": ["build", "test"], "retries": 2, "owner": {"team": "core"}, "timeout": "30"}`,
+		} {
+			var pipeline decodePipeline
+
+			require.NoError(t, obj.Decode(&pipeline))
+			assert.Equal(t, expected, pipeline)
+		}
+	})
+
+	t.Run("should decode toml content", func(t *testing.T) {
+		var pipeline decodePipeline
+
+		obj := content.Object("name = \"sample\"\nstages = [\"build\", \"test\"]\nmax_retries = 2\ntimeout = \"30s\"\n\n[owner]\nteam = \"core\"\n")
+
+		require.NoError(t, obj.Decode(&pipeline))
+		assert.Equal(t, expected, pipeline)
+	})
+
+	t.Run("should decode csv content as list of objects", func(t *testing.T) {
+		var owners []decodeOwner
+
+		require.NoError(t, content.Object("team,lead\ncore,john\nplatform,jane\n").Decode(&owners))
+		assert.Equal(t, []decodeOwner{{Team: "core"}, {Team: "platform"}}, owners)
+	})
+
+	t.Run("should fail on unknown fields in strict mode", func(t *testing.T) {
+		var pipeline decodePipeline
+
+		obj := content.Object(`{"name": "sample", "region": "eu"}`)
+
+		require.NoError(t, obj.Decode(&pipeline))
+
+		err := obj.Decode(&pipeline, content.WithStrict())
+		assert.EqualError(t, err, `decoding json content errored: unknown field "region"`)
+	})
+
+	t.Run("should not decode json content by the yaml tags", func(t *testing.T) {
+		var pipeline decodePipeline
+
+		require.NoError(t, content.Object(`{"name": "sample", "max_retries": 3}`).Decode(&pipeline))
+		assert.Zero(t, pipeline.Retries)
+
+		err := content.Object(`{"name": "sample", "max_retries": 3}`).Decode(&pipeline, content.WithStrict())
+		assert.EqualError(t, err, `decoding json content errored: unknown field "max_retries"`)
+	})
+
+	t.Run("should fail on duplicate keys unless allowed", func(t *testing.T) {
+		var pipeline decodePipeline
+
+		obj := content.Object("name: sample\nstages: [build]\nname: other\n")
+
+		err := obj.Decode(&pipeline)
+		assert.EqualError(t, err, `decoding yaml content errored at line 3, column 1: mapping key "name" already defined at [1:1]`)
+
+		require.NoError(t, obj.Decode(&pipeline, content.WithDuplicateKeys()))
+		assert.Equal(t, "other", pipeline.Name)
+
+		obj = `{"name": "sample", "name": "other"}`
+
+		err = obj.Decode(&pipeline)
+		assert.EqualError(t, err, `decoding json content errored at line 1, column 20: mapping key "name" already defined at [1:2]`)
+
+		require.NoError(t, obj.Decode(&pipeline, content.WithDuplicateKeys()))
+		assert.Equal(t, "other", pipeline.Name)
+	})
+
+	t.Run("should fail to decode xml content", func(t *testing.T) {
+		var pipeline decodePipeline
+
+		err := content.Object("<pipeline><name>sample</name></pipeline>").Decode(&pipeline)
+		assert.EqualError(t, err, "cannot decode content of type 'xml', only YAML, JSON, TOML and CSV are supported")
+	})
+}
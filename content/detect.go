@@ -0,0 +1,229 @@
+package content
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ambiguityMargin is the difference in confidence below which the top candidates are reported as ambiguous.
+	ambiguityMargin = 0.1
+	// hintConfidence is added to the candidates matching the extension or the MIME type passed in DetectHints.
+	hintConfidence = 0.4
+	// csvMinimalSize is the number of rows and columns below which CSV detection is less confident.
+	csvMinimalSize = 3
+	// confidencePrecision rounds the confidence to two decimals.
+	confidencePrecision = 100
+
+	confidenceJSON       = 0.95
+	confidenceXML        = 0.9
+	confidenceCSV        = 0.6
+	confidenceMinimalCSV = 0.5
+	confidenceYAML       = 0.7
+	confidenceJSONAsYAML = 0.5
+	confidenceYAMLScalar = 0.2
+	confidenceTOML       = 0.7
+	confidenceString     = 0.3
+)
+
+// detectionOrder breaks the ties between candidates of equal confidence, matching the order of CheckFileType.
+var detectionOrder = []string{FileTypeJSON, FileTypeXML, FileTypeCSV, FileTypeYAML, FileTypeTOML, FileTypeString}
+
+var extensionFormats = map[string]string{
+	".json": FileTypeJSON,
+	".yaml": FileTypeYAML,
+	".yml":  FileTypeYAML,
+	".xml":  FileTypeXML,
+	".csv":  FileTypeCSV,
+	".toml": FileTypeTOML,
+	".txt":  FileTypeString,
+}
+
+var mimeFormats = map[string]string{
+	"application/json":   FileTypeJSON,
+	"text/json":          FileTypeJSON,
+	"application/yaml":   FileTypeYAML,
+	"application/x-yaml": FileTypeYAML,
+	"text/yaml":          FileTypeYAML,
+	"text/x-yaml":        FileTypeYAML,
+	"application/xml":    FileTypeXML,
+	"text/xml":           FileTypeXML,
+	"text/csv":           FileTypeCSV,
+	"application/toml":   FileTypeTOML,
+	"text/plain":         FileTypeString,
+}
+
+// DetectHints holds the hints used by Detect to break the ties between formats the content could be parsed as.
+type DetectHints struct {
+	// Extension is the extension of the file the content was read from, ex: '.yaml' or 'yaml'. A file path works as well.
+	Extension string `json:"extension,omitempty" yaml:"extension,omitempty"`
+	// MIMEType is the media type the content was served with, ex: 'application/json; charset=utf-8'.
+	MIMEType string `json:"mime_type,omitempty" yaml:"mime_type,omitempty"`
+}
+
+// Candidate is a format the content could be parsed as, with the confidence from 0 to 1 and the reasons for it.
+type Candidate struct {
+	Format     string   `json:"format,omitempty" yaml:"format,omitempty"`
+	Confidence float64  `json:"confidence,omitempty" yaml:"confidence,omitempty"`
+	Reasons    []string `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+}
+
+// Detection is the result of Detect.
+type Detection struct {
+	// Format is the format of the candidate with the highest confidence, FileTypeUnknown when there are no candidates.
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	// Candidates lists every format the content could be parsed as, ordered by confidence.
+	Candidates []Candidate `json:"candidates,omitempty" yaml:"candidates,omitempty"`
+	// Ambiguous is set when the confidence of the top two candidates are too close to pick one reliably.
+	Ambiguous bool `json:"ambiguous,omitempty" yaml:"ambiguous,omitempty"`
+}
+
+// Detect identifies every format the content could be parsed as with a confidence score and the reasons, unlike CheckFileType
+// which returns the first format matching in a fixed order. The hints passed are used to break the ties, ex: 'a,b\nc,d' is reported as CSV
+// unless the file extension says YAML.
+func (obj Object) Detect(log *logrus.Logger, hints DetectHints) Detection {
+	log.Debug("detecting the input file type with confidence scores")
+
+	content := normalizeContent(string(obj))
+	candidates := make(map[string]*Candidate)
+
+	add := func(format string, confidence float64, reason string) {
+		candidate, ok := candidates[format]
+		if !ok {
+			candidate = &Candidate{Format: format}
+			candidates[format] = candidate
+		}
+
+		candidate.Confidence += confidence
+		candidate.Reasons = append(candidate.Reasons, reason)
+	}
+
+	detectStructured(log, content, add)
+
+	if IsJSONString(content) || IsYAMLString(log, content) {
+		add(FileTypeString, confidenceString, "parses as a scalar string")
+	}
+
+	if format, ok := hints.extensionFormat(); ok {
+		if _, exists := candidates[format]; exists {
+			add(format, hintConfidence, fmt.Sprintf("file extension '%s' matches", hints.Extension))
+		}
+	}
+
+	if format, ok := hints.mimeFormat(); ok {
+		if _, exists := candidates[format]; exists {
+			add(format, hintConfidence, fmt.Sprintf("MIME type '%s' matches", hints.MIMEType))
+		}
+	}
+
+	detection := Detection{Format: FileTypeUnknown, Candidates: make([]Candidate, 0, len(candidates))}
+
+	for _, format := range detectionOrder {
+		if candidate, ok := candidates[format]; ok {
+			candidate.Confidence = math.Round(math.Min(candidate.Confidence, 1)*confidencePrecision) / confidencePrecision
+			detection.Candidates = append(detection.Candidates, *candidate)
+		}
+	}
+
+	sort.SliceStable(detection.Candidates, func(i, j int) bool {
+		return detection.Candidates[i].Confidence > detection.Candidates[j].Confidence
+	})
+
+	if len(detection.Candidates) != 0 {
+		detection.Format = detection.Candidates[0].Format
+	}
+
+	if len(detection.Candidates) > 1 {
+		detection.Ambiguous = detection.Candidates[0].Confidence-detection.Candidates[1].Confidence < ambiguityMargin
+	}
+
+	log.Debugf("input file type detected as %s, ambiguous: %t", detection.Format, detection.Ambiguous)
+
+	return detection
+}
+
+// detectStructured adds the candidates for the structured formats the content parses as.
+func detectStructured(log *logrus.Logger, content string, add func(format string, confidence float64, reason string)) {
+	isJSON := IsJSON(content) && !IsJSONString(content)
+	if isJSON {
+		add(FileTypeJSON, confidenceJSON, "parses as JSON")
+	}
+
+	if IsXML(content) {
+		add(FileTypeXML, confidenceXML, "parses as XML with a single root element")
+	}
+
+	if IsCSV(content) {
+		records, _ := csv.NewReader(strings.NewReader(content)).ReadAll()
+		reason := fmt.Sprintf("parses as CSV with %d rows of %d columns", len(records), len(records[0]))
+
+		if len(records) >= csvMinimalSize || len(records[0]) >= csvMinimalSize {
+			add(FileTypeCSV, confidenceCSV, reason)
+		} else {
+			add(FileTypeCSV, confidenceMinimalCSV, reason)
+		}
+	}
+
+	switch {
+	case isJSON:
+		add(FileTypeYAML, confidenceJSONAsYAML, "parses as YAML, since JSON is a subset of YAML")
+	case IsYAML(log, content):
+		add(FileTypeYAML, confidenceYAML, "parses as a YAML mapping or sequence")
+	case !IsJSONString(content) && IsYAMLString(log, content):
+		add(FileTypeYAML, confidenceYAMLScalar, "parses as a YAML scalar")
+	}
+
+	var table map[string]any
+	if metadata, err := toml.Decode(content, &table); err == nil && len(metadata.Keys()) != 0 {
+		add(FileTypeTOML, confidenceTOML, fmt.Sprintf("parses as TOML with %d keys", len(metadata.Keys())))
+	}
+}
+
+func (hints DetectHints) extensionFormat() (string, bool) {
+	if len(hints.Extension) == 0 {
+		return "", false
+	}
+
+	extension := filepath.Ext(hints.Extension)
+	if len(extension) == 0 {
+		extension = "." + hints.Extension
+	}
+
+	format, ok := extensionFormats[strings.ToLower(extension)]
+
+	return format, ok
+}
+
+func (hints DetectHints) mimeFormat() (string, bool) {
+	if len(hints.MIMEType) == 0 {
+		return "", false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(hints.MIMEType)
+	if err != nil {
+		return "", false
+	}
+
+	if format, ok := mimeFormats[mediaType]; ok {
+		return format, true
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return FileTypeJSON, true
+	case strings.HasSuffix(mediaType, "+xml"):
+		return FileTypeXML, true
+	case strings.HasSuffix(mediaType, "+yaml"):
+		return FileTypeYAML, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,70 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func candidateFormats(detection content.Detection) []string {
+	formats := make([]string, 0, len(detection.Candidates))
+	for _, candidate := range detection.Candidates {
+		formats = append(formats, candidate.Format)
+	}
+
+	return formats
+}
+
+func TestObject_Detect(t *testing.T) {
+	t.Run("should list json and yaml as candidates for json content", func(t *testing.T) {
+		detection := content.Object(`{"name": "testing"}`).Detect(log, content.DetectHints{})
+
+		assert.Equal(t, content.FileTypeJSON, detection.Format)
+		assert.Equal(t, []string{content.FileTypeJSON, content.FileTypeYAML}, candidateFormats(detection))
+		assert.InDelta(t, 0.95, detection.Candidates[0].Confidence, 0.001)
+		assert.Equal(t, []string{"parses as YAML, since JSON is a subset of YAML"}, detection.Candidates[1].Reasons)
+		assert.False(t, detection.Ambiguous)
+	})
+
+	t.Run("should prefer csv for content that is also yaml without hints", func(t *testing.T) {
+		detection := content.Object("a,b\nc,d").Detect(log, content.DetectHints{})
+
+		assert.Equal(t, content.FileTypeCSV, detection.Format)
+		assert.Equal(t, []string{content.FileTypeCSV, content.FileTypeString, content.FileTypeYAML}, candidateFormats(detection))
+		assert.Equal(t, []string{"parses as CSV with 2 rows of 2 columns"}, detection.Candidates[0].Reasons)
+	})
+
+	t.Run("should break the tie with the file extension", func(t *testing.T) {
+		detection := content.Object("a,b\nc,d").Detect(log, content.DetectHints{Extension: "pipelines/config.yaml"})
+
+		assert.Equal(t, content.FileTypeYAML, detection.Format)
+		assert.InDelta(t, 0.6, detection.Candidates[0].Confidence, 0.001)
+		assert.Equal(t, []string{"parses as a YAML scalar", "file extension 'pipelines/config.yaml' matches"}, detection.Candidates[0].Reasons)
+		assert.True(t, detection.Ambiguous)
+	})
+
+	t.Run("should break the tie with the MIME type", func(t *testing.T) {
+		detection := content.Object("name = \"sample\"\n").Detect(log, content.DetectHints{MIMEType: "application/toml; charset=utf-8"})
+
+		require.NotEmpty(t, detection.Candidates)
+		assert.Equal(t, content.FileTypeTOML, detection.Format)
+		assert.InDelta(t, 1.0, detection.Candidates[0].Confidence, 0.001)
+		assert.False(t, detection.Ambiguous)
+	})
+
+	t.Run("should ignore hints for formats the content does not parse as", func(t *testing.T) {
+		detection := content.Object("name: sample\nkind: pipeline\n").Detect(log, content.DetectHints{Extension: "json", MIMEType: "application/json"})
+
+		assert.Equal(t, content.FileTypeYAML, detection.Format)
+		assert.Equal(t, []string{content.FileTypeYAML}, candidateFormats(detection))
+	})
+
+	t.Run("should report unknown content", func(t *testing.T) {
+		detection := content.Object("{name: [}").Detect(log, content.DetectHints{})
+
+		assert.Equal(t, content.FileTypeUnknown, detection.Format)
+		assert.Empty(t, detection.Candidates)
+	})
+}
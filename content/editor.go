@@ -0,0 +1,654 @@
+package content
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Editor edits YAML content by path while retaining the comments, anchors, formatting and order of keys of the parts left untouched.
+// The paths are made of keys separated by '.' and sequence indexes in brackets, optionally prefixed with '$',
+// ex: '$.stages[0].image'. Keys with '.' or brackets are quoted in brackets, ex: "labels['app.kubernetes.io/name']".
+// Only the first document of multi document content is edited.
+type Editor struct {
+	log            *logrus.Logger
+	file           *ast.File
+	indent         int
+	indentSequence bool
+	json           bool
+}
+
+// NewEditor parses the YAML content for editing, returning *ParseError when it is malformed.
+func NewEditor(log *logrus.Logger, obj Object) (editor *Editor, err error) {
+	content := normalizeContent(string(obj))
+
+	// github.com/goccy/go-yaml can produce panics, the content is reported as not editable when it does.
+	err = &errors.CommonError{Message: "parsing the content for editing failed"}
+	defer handlePanic(log)
+
+	file, parseErr := parser.ParseBytes([]byte(content), parser.ParseComments)
+	if parseErr != nil {
+		return nil, ValidateYAML(log, content)
+	}
+
+	if len(file.Docs) == 0 {
+		file.Docs = append(file.Docs, ast.Document(nil, nil))
+	}
+
+	editor = &Editor{log: log, file: file, indent: defaultYAMLIndent, indentSequence: true, json: IsJSON(content)}
+	editor.detectIndent()
+
+	return editor, nil
+}
+
+// Object returns the edited content.
+func (editor *Editor) Object() Object {
+	return Object(strings.TrimRight(editor.file.String(), "\n") + "\n")
+}
+
+// Set sets the value at the path, adding the keys missing along the path.
+// The value replaces the existing one, retaining its anchor and comment, or is added at the end of the mapping otherwise.
+// Setting the index next to the last item of a sequence appends the value.
+func (editor *Editor) Set(path string, value any) error {
+	segments, err := parseEditPath(path)
+	if err != nil {
+		return err
+	}
+
+	doc := editor.file.Docs[0]
+
+	if len(segments) == 0 || isEmptyNode(doc.Body) {
+		for index := len(segments) - 1; index >= 0; index-- {
+			if segments[index].isIndex {
+				return &errors.CommonError{Message: fmt.Sprintf("cannot set '%s', the sequence at '%s' does not exist", path, joinEditPath(segments[:index]))}
+			}
+
+			value = yaml.MapSlice{{Key: segments[index].key, Value: value}}
+		}
+
+		body, err := editor.valueNode(value)
+		if err != nil {
+			return err
+		}
+
+		doc.Body = body
+
+		return nil
+	}
+
+	container, depth, err := editor.lookup(segments)
+	if err != nil {
+		return err
+	}
+
+	// the keys missing along the path are set as nested mappings.
+	for index := len(segments) - 1; index > depth; index-- {
+		if segments[index].isIndex {
+			return &errors.CommonError{Message: fmt.Sprintf("cannot set '%s', the sequence at '%s' does not exist", path, joinEditPath(segments[:index]))}
+		}
+
+		value = yaml.MapSlice{{Key: segments[index].key, Value: value}}
+	}
+
+	if isEmptyNode(container) {
+		if segments[depth].isIndex && segments[depth].index != 0 {
+			return &errors.CommonError{Message: fmt.Sprintf("index %d is out of range, the sequence has 0 items", segments[depth].index)}
+		}
+
+		return editor.Set(joinEditPath(segments[:depth]), containerValue(segments[depth], value))
+	}
+
+	switch typedContainer := container.(type) {
+	case *ast.MappingNode:
+		if segments[depth].isIndex {
+			return notMapping(path, segments[:depth])
+		}
+
+		return editor.setKey(typedContainer, segments[depth].key, value)
+	case *ast.SequenceNode:
+		if !segments[depth].isIndex {
+			return notSequence(path, segments[:depth])
+		}
+
+		if segments[depth].index == len(typedContainer.Values) {
+			return editor.insertItem(typedContainer, segments[depth].index, value)
+		}
+
+		return editor.setItem(typedContainer, segments[depth].index, value)
+	default:
+		return &errors.CommonError{Message: fmt.Sprintf("cannot set '%s', '%s' is a scalar", path, joinEditPath(segments[:depth]))}
+	}
+}
+
+// Delete removes the key or the sequence item at the path.
+func (editor *Editor) Delete(path string) error {
+	segments, err := parseEditPath(path)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) == 0 {
+		return &errors.CommonError{Message: "cannot delete the root of the document"}
+	}
+
+	container, err := editor.parent(path, segments)
+	if err != nil {
+		return err
+	}
+
+	last := segments[len(segments)-1]
+
+	switch typedContainer := container.(type) {
+	case *ast.MappingNode:
+		index := mappingIndex(typedContainer, last.key)
+		if last.isIndex || index < 0 {
+			return &errors.CommonError{Message: fmt.Sprintf("path '%s' not found", path)}
+		}
+
+		retainHeadComment(typedContainer.Values, index)
+		typedContainer.Values = append(typedContainer.Values[:index], typedContainer.Values[index+1:]...)
+
+		if len(typedContainer.Values) == 0 && !typedContainer.IsFlowStyle {
+			editor.inlineEmptyMapping(typedContainer, segments[:len(segments)-1])
+		}
+	case *ast.SequenceNode:
+		if !last.isIndex || last.index >= len(typedContainer.Values) {
+			return &errors.CommonError{Message: fmt.Sprintf("path '%s' not found", path)}
+		}
+
+		if len(typedContainer.ValueHeadComments) == len(typedContainer.Values) {
+			typedContainer.ValueHeadComments = append(typedContainer.ValueHeadComments[:last.index], typedContainer.ValueHeadComments[last.index+1:]...)
+		}
+
+		if len(typedContainer.Entries) == len(typedContainer.Values) {
+			typedContainer.Entries = append(typedContainer.Entries[:last.index], typedContainer.Entries[last.index+1:]...)
+		}
+
+		typedContainer.Values = append(typedContainer.Values[:last.index], typedContainer.Values[last.index+1:]...)
+	default:
+		return &errors.CommonError{Message: fmt.Sprintf("path '%s' not found", path)}
+	}
+
+	return nil
+}
+
+// retainHeadComment moves the head comment of the entry at the index, which is being deleted, to the entry following it.
+func retainHeadComment(values []*ast.MappingValueNode, index int) {
+	comment := values[index].GetComment()
+	if comment == nil || index+1 == len(values) {
+		return
+	}
+
+	comments := make([]*token.Token, 0, len(comment.Comments))
+	for _, group := range []*ast.CommentGroupNode{comment, values[index+1].GetComment()} {
+		if group == nil {
+			continue
+		}
+
+		for _, line := range group.Comments {
+			comments = append(comments, line.Token)
+		}
+	}
+
+	if err := values[index+1].SetComment(ast.CommentGroup(comments)); err != nil {
+		return
+	}
+}
+
+// inlineEmptyMapping writes the block mapping left without keys as '{}' on the line of its key, as an empty block would not parse.
+func (editor *Editor) inlineEmptyMapping(mapping *ast.MappingNode, segments []editSegment) {
+	mapping.IsFlowStyle = true
+
+	if len(segments) == 0 {
+		return
+	}
+
+	container, _, err := editor.lookup(segments)
+	if err != nil {
+		return
+	}
+
+	parent, ok := container.(*ast.MappingNode)
+	if !ok || segments[len(segments)-1].isIndex {
+		return
+	}
+
+	if index := mappingIndex(parent, segments[len(segments)-1].key); index >= 0 {
+		start := mapping.Start.Clone()
+		position := *parent.Values[index].Key.GetToken().Position
+		start.Position = &position
+		mapping.Start = start
+	}
+}
+
+// Insert inserts the value in the sequence before the item at the index the path ends with, ex: '$.stages[1]'.
+// Inserting at the index next to the last item appends the value.
+func (editor *Editor) Insert(path string, value any) error {
+	segments, err := parseEditPath(path)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) == 0 || !segments[len(segments)-1].isIndex {
+		return &errors.CommonError{Message: fmt.Sprintf("cannot insert at '%s', the path should end with a sequence index", path)}
+	}
+
+	container, err := editor.parent(path, segments)
+	if err != nil {
+		return err
+	}
+
+	if isEmptyNode(container) {
+		return editor.Set(joinEditPath(segments[:len(segments)-1]), containerValue(segments[len(segments)-1], value))
+	}
+
+	sequence, ok := container.(*ast.SequenceNode)
+	if !ok {
+		return notSequence(path, segments[:len(segments)-1])
+	}
+
+	return editor.insertItem(sequence, segments[len(segments)-1].index, value)
+}
+
+// lookup returns the deepest container along the path, along with the index of the segment missing in it.
+// The container of the last segment is returned when the complete path exists.
+func (editor *Editor) lookup(segments []editSegment) (ast.Node, int, error) {
+	current := unwrapNode(editor.file.Docs[0].Body)
+
+	for depth, segment := range segments {
+		if depth == len(segments)-1 {
+			return current, depth, nil
+		}
+
+		var next ast.Node
+
+		switch typedCurrent := current.(type) {
+		case *ast.MappingNode:
+			if index := mappingIndex(typedCurrent, segment.key); !segment.isIndex && index >= 0 {
+				next = typedCurrent.Values[index].Value
+			}
+		case *ast.SequenceNode:
+			if segment.isIndex && segment.index < len(typedCurrent.Values) {
+				next = typedCurrent.Values[segment.index]
+			}
+		}
+
+		if next == nil {
+			return current, depth, nil
+		}
+
+		if _, ok := next.(*ast.AliasNode); ok {
+			return nil, 0, &errors.CommonError{Message: fmt.Sprintf("cannot edit through the alias at '%s'", joinEditPath(segments[:depth+1]))}
+		}
+
+		current = unwrapNode(next)
+	}
+
+	return current, len(segments) - 1, nil
+}
+
+// parent returns the container of the last segment of the path, which should exist.
+func (editor *Editor) parent(path string, segments []editSegment) (ast.Node, error) {
+	container, depth, err := editor.lookup(segments)
+	if err != nil {
+		return nil, err
+	}
+
+	if depth != len(segments)-1 {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("path '%s' not found", path)}
+	}
+
+	return container, nil
+}
+
+func (editor *Editor) setKey(mapping *ast.MappingNode, key string, value any) error {
+	entry, err := editor.entryNode(mapping, key, value)
+	if err != nil {
+		return err
+	}
+
+	index := mappingIndex(mapping, key)
+	if index < 0 {
+		mapping.Values = append(mapping.Values, entry)
+
+		return nil
+	}
+
+	existing := mapping.Values[index]
+	entry.Value = retainDecorations(existing.Value, entry.Value)
+	existing.Value = entry.Value
+
+	return nil
+}
+
+func (editor *Editor) setItem(sequence *ast.SequenceNode, index int, value any) error {
+	if index < 0 || index >= len(sequence.Values) {
+		return outOfRange(index, sequence)
+	}
+
+	item, _, err := editor.itemNode(sequence, value)
+	if err != nil {
+		return err
+	}
+
+	sequence.Values[index] = retainDecorations(sequence.Values[index], item)
+
+	return nil
+}
+
+func (editor *Editor) insertItem(sequence *ast.SequenceNode, index int, value any) error {
+	if index < 0 || index > len(sequence.Values) {
+		return outOfRange(index, sequence)
+	}
+
+	item, entry, err := editor.itemNode(sequence, value)
+	if err != nil {
+		return err
+	}
+
+	if len(sequence.ValueHeadComments) == len(sequence.Values) {
+		sequence.ValueHeadComments = insertAt(sequence.ValueHeadComments, index, nil)
+	}
+
+	if len(sequence.Entries) == len(sequence.Values) {
+		sequence.Entries = insertAt(sequence.Entries, index, entry)
+	}
+
+	sequence.Values = insertAt(sequence.Values, index, item)
+
+	return nil
+}
+
+// entryNode returns the key and value as a node indented to the keys of the mapping, in the style of the mapping.
+func (editor *Editor) entryNode(mapping *ast.MappingNode, key string, value any) (*ast.MappingValueNode, error) {
+	column := mapping.Values[0].Key.GetToken().Position.Column
+
+	node, err := editor.snippet(yaml.MapSlice{{Key: key, Value: value}}, column, mapping.IsFlowStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	snippetMapping, ok := node.(*ast.MappingNode)
+	if !ok || len(snippetMapping.Values) != 1 {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("encoding the value of '%s' did not produce a mapping", key)}
+	}
+
+	return snippetMapping.Values[0], nil
+}
+
+// itemNode returns the value as an item indented to the items of the sequence, in the style of the sequence.
+func (editor *Editor) itemNode(sequence *ast.SequenceNode, value any) (ast.Node, *ast.SequenceEntryNode, error) {
+	node, err := editor.snippet([]any{value}, sequence.Start.Position.Column, sequence.IsFlowStyle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snippetSequence, ok := node.(*ast.SequenceNode)
+	if !ok || len(snippetSequence.Values) != 1 {
+		return nil, nil, &errors.CommonError{Message: "encoding the value did not produce a sequence"}
+	}
+
+	var entry *ast.SequenceEntryNode
+	if len(snippetSequence.Entries) == 1 {
+		entry = snippetSequence.Entries[0]
+	}
+
+	return snippetSequence.Values[0], entry, nil
+}
+
+func (editor *Editor) valueNode(value any) (ast.Node, error) {
+	return editor.snippet(value, 1, false)
+}
+
+// snippet encodes the value and parses it back indented to the column, so that it is written the same way as the content around it.
+// The keys of flow style values are quoted, and JSON content is written as JSON.
+func (editor *Editor) snippet(value any, column int, flow bool) (node ast.Node, err error) {
+	// github.com/goccy/go-yaml can produce panics, the value is reported as not encodable when it does.
+	err = &errors.CommonError{Message: "encoding value failed"}
+	defer handlePanic(editor.log)
+
+	opts := []yaml.EncodeOption{
+		yaml.Indent(editor.indent), yaml.IndentSequence(editor.indentSequence), yaml.Flow(flow), yaml.UseLiteralStyleIfMultiline(!flow),
+	}
+
+	if flow && editor.json {
+		opts = append(opts, yaml.JSON())
+	}
+
+	out, marshalErr := yaml.MarshalWithOptions(value, opts...)
+	if marshalErr != nil {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("encoding value errored with '%v'", marshalErr)}
+	}
+
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	for index, line := range lines {
+		if len(line) != 0 {
+			lines[index] = strings.Repeat(" ", column-1) + line
+		}
+	}
+
+	file, err := parser.ParseBytes([]byte(strings.Join(lines, "\n")), parser.ParseComments)
+	if err != nil || len(file.Docs) == 0 {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("parsing encoded value errored with '%v'", err)}
+	}
+
+	if flow {
+		quoteKeys(file.Docs[0].Body)
+	}
+
+	return file.Docs[0].Body, nil
+}
+
+// quoteKeys double quotes the plain keys of the mappings in the node, as the keys of JSON should be.
+func quoteKeys(node ast.Node) {
+	for _, value := range ast.Filter(ast.MappingValueType, node) {
+		entry, ok := value.(*ast.MappingValueNode)
+		if !ok {
+			continue
+		}
+
+		if key, ok := entry.Key.(*ast.StringNode); ok && key.Token.Type == token.StringType {
+			key.Token.Type = token.DoubleQuoteType
+		}
+	}
+}
+
+// detectIndent identifies the indentation of the content, so that the values set are indented the same way.
+func (editor *Editor) detectIndent() {
+	if editor.file.Docs[0].Body == nil {
+		return
+	}
+
+	detectedIndent, detectedSequence := false, false
+
+	for _, node := range ast.Filter(ast.MappingValueType, editor.file.Docs[0].Body) {
+		entry, ok := node.(*ast.MappingValueNode)
+		if !ok {
+			continue
+		}
+
+		keyColumn := entry.Key.GetToken().Position.Column
+
+		switch value := unwrapNode(entry.Value).(type) {
+		case *ast.MappingNode:
+			if !detectedIndent && !value.IsFlowStyle && len(value.Values) != 0 {
+				if indent := value.Values[0].Key.GetToken().Position.Column - keyColumn; indent > 0 {
+					editor.indent, detectedIndent = indent, true
+				}
+			}
+		case *ast.SequenceNode:
+			if !detectedSequence && !value.IsFlowStyle {
+				editor.indentSequence, detectedSequence = value.Start.Position.Column > keyColumn, true
+			}
+		}
+
+		if detectedIndent && detectedSequence {
+			return
+		}
+	}
+}
+
+// retainDecorations moves the anchor and the comment of the existing value to the value replacing it.
+func retainDecorations(existing, replacement ast.Node) ast.Node {
+	if anchor, ok := existing.(*ast.AnchorNode); ok {
+		anchor.Value = retainDecorations(anchor.Value, replacement)
+
+		return anchor
+	}
+
+	if _, ok := replacement.(ast.ScalarNode); ok && existing.GetComment() != nil && replacement.GetComment() == nil {
+		if err := replacement.SetComment(existing.GetComment()); err != nil {
+			return replacement
+		}
+	}
+
+	return replacement
+}
+
+func unwrapNode(node ast.Node) ast.Node {
+	switch typedNode := node.(type) {
+	case *ast.AnchorNode:
+		return unwrapNode(typedNode.Value)
+	case *ast.TagNode:
+		return unwrapNode(typedNode.Value)
+	default:
+		return node
+	}
+}
+
+func isEmptyNode(node ast.Node) bool {
+	switch typedNode := node.(type) {
+	case nil, *ast.NullNode:
+		return true
+	case *ast.MappingNode:
+		return len(typedNode.Values) == 0
+	case *ast.SequenceNode:
+		return len(typedNode.Values) == 0
+	default:
+		return false
+	}
+}
+
+// containerValue returns the value set in an empty container for the segment.
+func containerValue(segment editSegment, value any) any {
+	if segment.isIndex {
+		return []any{value}
+	}
+
+	return yaml.MapSlice{{Key: segment.key, Value: value}}
+}
+
+func mappingIndex(mapping *ast.MappingNode, key string) int {
+	for index, value := range mapping.Values {
+		if value.Key.GetToken().Value == key {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func insertAt[T any](values []T, index int, value T) []T {
+	values = append(values, value)
+	copy(values[index+1:], values[index:])
+	values[index] = value
+
+	return values
+}
+
+func outOfRange(index int, sequence *ast.SequenceNode) error {
+	return &errors.CommonError{Message: fmt.Sprintf("index %d is out of range, the sequence has %d items", index, len(sequence.Values))}
+}
+
+func notMapping(path string, segments []editSegment) error {
+	return &errors.CommonError{Message: fmt.Sprintf("cannot edit '%s', '%s' is not a mapping", path, joinEditPath(segments))}
+}
+
+func notSequence(path string, segments []editSegment) error {
+	return &errors.CommonError{Message: fmt.Sprintf("cannot edit '%s', '%s' is not a sequence", path, joinEditPath(segments))}
+}
+
+type editSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseEditPath parses the path into keys and sequence indexes, ex: "$.stages[0]['app.name']".
+func parseEditPath(path string) ([]editSegment, error) {
+	remaining := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	segments := make([]editSegment, 0)
+
+	invalid := func(reason string) error {
+		return &errors.CommonError{Message: fmt.Sprintf("invalid path '%s': %s", path, reason)}
+	}
+
+	for len(remaining) != 0 {
+		switch {
+		case remaining[0] == '[':
+			end := strings.IndexByte(remaining, ']')
+			if end < 0 {
+				return nil, invalid("unterminated '['")
+			}
+
+			inner := remaining[1:end]
+
+			if quoted := len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]; quoted {
+				segments = append(segments, editSegment{key: inner[1 : len(inner)-1]})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, invalid(fmt.Sprintf("'%s' is not a valid index", inner))
+				}
+
+				segments = append(segments, editSegment{index: index, isIndex: true})
+			}
+
+			remaining = remaining[end+1:]
+		default:
+			if remaining[0] == '.' {
+				remaining = remaining[1:]
+			}
+
+			end := strings.IndexAny(remaining, ".[")
+			if end < 0 {
+				end = len(remaining)
+			}
+
+			if end == 0 {
+				return nil, invalid("empty key")
+			}
+
+			segments = append(segments, editSegment{key: remaining[:end]})
+			remaining = remaining[end:]
+		}
+	}
+
+	return segments, nil
+}
+
+func joinEditPath(segments []editSegment) string {
+	var path strings.Builder
+
+	path.WriteString("$")
+
+	for _, segment := range segments {
+		switch {
+		case segment.isIndex:
+			path.WriteString(fmt.Sprintf("[%d]", segment.index))
+		case strings.ContainsAny(segment.key, ".[]"):
+			path.WriteString(fmt.Sprintf("['%s']", segment.key))
+		default:
+			path.WriteString("." + segment.key)
+		}
+	}
+
+	return path.String()
+}
@@ -0,0 +1,227 @@
+package content_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const editorContent = `# pipeline configuration
+name: sample # name of the pipeline
+
+defaults: &defaults
+  image: golang   # build image
+  retries: 2
+
+stages:
+    - name: build
+      <<: *defaults
+    # tests run after build
+    - name: test
+      tags: [unit, race]
+labels:
+  team: core
+`
+
+func TestEditor_Set(t *testing.T) {
+	t.Run("should replace the values retaining the comments, anchors and order", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), editorContent)
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Set("$.name", "release"))
+		require.NoError(t, editor.Set("defaults.image", "golang:1.25"))
+		require.NoError(t, editor.Set("stages[1].tags[1]", "integration"))
+
+		assert.Equal(t, `# pipeline configuration
+name: release # name of the pipeline
+
+defaults: &defaults
+  image: golang:1.25 # build image
+  retries: 2
+
+stages:
+    - name: build
+      <<: *defaults
+    # tests run after build
+    - name: test
+      tags: [unit, integration]
+labels:
+  team: core
+`, editor.Object().String())
+	})
+
+	t.Run("should add the keys missing along the path", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), editorContent)
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Set("labels['app.kubernetes.io/name']", "sample"))
+		require.NoError(t, editor.Set("stages[0].resources.limits", map[string]any{"cpu": "2"}))
+		require.NoError(t, editor.Set("stages[2]", map[string]any{"name": "deploy"}))
+		require.NoError(t, editor.Set("owner.team", "platform"))
+
+		assert.Equal(t, `# pipeline configuration
+name: sample # name of the pipeline
+
+defaults: &defaults
+  image: golang # build image
+  retries: 2
+
+stages:
+    - name: build
+      <<: *defaults
+      resources:
+        limits:
+          cpu: "2"
+    # tests run after build
+    - name: test
+      tags: [unit, race]
+    - name: deploy
+labels:
+  team: core
+  app.kubernetes.io/name: sample
+owner:
+  team: platform
+`, editor.Object().String())
+	})
+
+	t.Run("should replace a scalar with a mapping", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), "name: sample\nretries: 2\n")
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Set("retries", map[string]any{"count": 3}))
+		assert.Equal(t, "name: sample\nretries:\n  count: 3\n", editor.Object().String())
+	})
+
+	t.Run("should set the values in empty content", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), "")
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Set("pipeline.name", "sample"))
+		assert.Equal(t, "pipeline:\n  name: sample\n", editor.Object().String())
+	})
+
+	t.Run("should keep json content as json", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), `{"a": 1, "b": ["x"]}`)
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Set("c", []any{1, 2}))
+		require.NoError(t, editor.Set("b[1]", map[string]any{"name": "y"}))
+		assert.Equal(t, "{\"a\": 1, \"b\": [\"x\", {\"name\": \"y\"}], \"c\": [1, 2]}\n", editor.Object().String())
+		assert.True(t, content.IsJSON(editor.Object().String()))
+	})
+
+	t.Run("should fail to set the values under a scalar", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), editorContent)
+		require.NoError(t, err)
+
+		assert.EqualError(t, editor.Set("name.first", "sample"), "cannot set 'name.first', '$.name' is a scalar")
+		assert.EqualError(t, editor.Set("stages[5]", "deploy"), "index 5 is out of range, the sequence has 2 items")
+		assert.EqualError(t, editor.Set("stages.name", "deploy"), "cannot edit 'stages.name', '$.stages' is not a sequence")
+		assert.EqualError(t, editor.Set("stages[0].<<.image", "alpine"), "cannot edit through the alias at '$.stages[0].<<'")
+	})
+}
+
+func TestEditor_Delete(t *testing.T) {
+	t.Run("should delete the keys and the sequence items", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), editorContent)
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Delete("defaults.retries"))
+		require.NoError(t, editor.Delete("stages[0]"))
+		require.NoError(t, editor.Delete("labels"))
+
+		assert.Equal(t, `# pipeline configuration
+name: sample # name of the pipeline
+
+defaults: &defaults
+  image: golang # build image
+
+stages:
+    # tests run after build
+    - name: test
+      tags: [unit, race]
+`, editor.Object().String())
+	})
+
+	t.Run("should write the mappings left empty so that they parse", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), "a:\n  b:\n    c: 1\nd: 3\n")
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Delete("a.b.c"))
+		assert.Equal(t, "a:\n  b: {}\nd: 3\n", editor.Object().String())
+
+		require.NoError(t, editor.Delete("a.b"))
+		assert.Equal(t, "a: {}\nd: 3\n", editor.Object().String())
+
+		var decoded map[string]any
+		require.NoError(t, editor.Object().Decode(&decoded))
+		assert.Equal(t, map[string]any{"a": map[string]any{}, "d": uint64(3)}, decoded)
+	})
+
+	t.Run("should keep the head comment of the key deleted", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), editorContent)
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Delete("name"))
+		assert.True(t, strings.HasPrefix(editor.Object().String(), "# pipeline configuration\n\ndefaults: &defaults\n"))
+	})
+
+	t.Run("should fail to delete the path that does not exist", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), editorContent)
+		require.NoError(t, err)
+
+		assert.EqualError(t, editor.Delete("labels.owner"), "path 'labels.owner' not found")
+		assert.EqualError(t, editor.Delete("stages[3]"), "path 'stages[3]' not found")
+		assert.EqualError(t, editor.Delete("$"), "cannot delete the root of the document")
+	})
+}
+
+func TestEditor_Insert(t *testing.T) {
+	t.Run("should insert the items in the sequences", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), editorContent)
+		require.NoError(t, err)
+
+		require.NoError(t, editor.Insert("stages[1]", map[string]any{"name": "lint"}))
+		require.NoError(t, editor.Insert("stages[2].tags[0]", "lint"))
+
+		assert.Equal(t, `# pipeline configuration
+name: sample # name of the pipeline
+
+defaults: &defaults
+  image: golang # build image
+  retries: 2
+
+stages:
+    - name: build
+      <<: *defaults
+    - name: lint
+    # tests run after build
+    - name: test
+      tags: [lint, unit, race]
+labels:
+  team: core
+`, editor.Object().String())
+	})
+
+	t.Run("should fail to insert without sequence index", func(t *testing.T) {
+		editor, err := content.NewEditor(logrus.New(), editorContent)
+		require.NoError(t, err)
+
+		assert.EqualError(t, editor.Insert("labels.owner", "me"), "cannot insert at 'labels.owner', the path should end with a sequence index")
+		assert.EqualError(t, editor.Insert("labels[0]", "me"), "cannot edit 'labels[0]', '$.labels' is not a sequence")
+		assert.EqualError(t, editor.Insert("stages[0", "me"), "invalid path 'stages[0': unterminated '['")
+	})
+}
+
+func TestNewEditor(t *testing.T) {
+	t.Run("should fail to edit malformed yaml", func(t *testing.T) {
+		_, err := content.NewEditor(logrus.New(), "name: [sample\n")
+
+		var parseError *content.ParseError
+		assert.ErrorAs(t, err, &parseError)
+	})
+}
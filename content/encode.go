@@ -0,0 +1,118 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/goccy/go-yaml"
+	"github.com/nikhilsbhat/common/errors"
+)
+
+const (
+	// QuoteStyleDouble quotes the YAML strings that require quoting with double quotes, this is the default.
+	QuoteStyleDouble = "double"
+	// QuoteStyleSingle quotes the YAML strings that require quoting with single quotes.
+	QuoteStyleSingle = "single"
+
+	defaultJSONIndent = 5
+	defaultYAMLIndent = 2
+	defaultXMLIndent  = 2
+	defaultTOMLIndent = 2
+	yamlDocumentStart = "---"
+)
+
+// EncodeOptions holds the options for encoding values to JSON, YAML, XML and TOML, so that the output matches the linters of the downstream tools.
+// The zero value encodes JSON with 5 spaces, YAML with 2 spaces in block style prefixed with document separator, XML and TOML with 2 spaces.
+type EncodeOptions struct {
+	// Indent is the number of spaces used for indentation, defaults are used when not set.
+	Indent int `json:"indent,omitempty" yaml:"indent,omitempty"`
+	// Compact encodes JSON in a single line.
+	Compact bool `json:"compact,omitempty" yaml:"compact,omitempty"`
+	// NoHTMLEscape disables escaping of <, > and & in JSON strings.
+	NoHTMLEscape bool `json:"no_html_escape,omitempty" yaml:"no_html_escape,omitempty"`
+	// FlowStyle encodes YAML in flow style instead of block style.
+	FlowStyle bool `json:"flow_style,omitempty" yaml:"flow_style,omitempty"`
+	// NoDocumentSeparator skips the '---' prefixed to YAML documents.
+	NoDocumentSeparator bool `json:"no_document_separator,omitempty" yaml:"no_document_separator,omitempty"`
+	// QuoteStyle is the quote used for the YAML strings that require quoting, one of QuoteStyleDouble or QuoteStyleSingle.
+	QuoteStyle string `json:"quote_style,omitempty" yaml:"quote_style,omitempty"`
+}
+
+// EncodeJSON encodes the value to JSON.
+func (opts EncodeOptions) EncodeJSON(value any) ([]byte, error) {
+	var out bytes.Buffer
+
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(!opts.NoHTMLEscape)
+
+	if !opts.Compact {
+		encoder.SetIndent("", strings.Repeat(" ", opts.indent(defaultJSONIndent)))
+	}
+
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+// EncodeYAML encodes the value to YAML.
+func (opts EncodeOptions) EncodeYAML(value any) ([]byte, error) {
+	encodeOptions := []yaml.EncodeOption{
+		yaml.Indent(opts.indent(defaultYAMLIndent)),
+		yaml.IndentSequence(true),
+		yaml.Flow(opts.FlowStyle),
+		yaml.UseLiteralStyleIfMultiline(!opts.FlowStyle),
+	}
+
+	switch opts.QuoteStyle {
+	case "", QuoteStyleDouble:
+	case QuoteStyleSingle:
+		encodeOptions = append(encodeOptions, yaml.UseSingleQuote(true))
+	default:
+		return nil, &errors.CommonError{
+			Message: fmt.Sprintf("unknown quote style '%s', should be one of '%s' or '%s'", opts.QuoteStyle, QuoteStyleDouble, QuoteStyleSingle),
+		}
+	}
+
+	out, err := yaml.MarshalWithOptions(value, encodeOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.NoDocumentSeparator {
+		return out, nil
+	}
+
+	return []byte(strings.Join([]string{yamlDocumentStart, string(out)}, "\n")), nil
+}
+
+// EncodeTOML encodes the value to TOML, the value should be a struct or a map.
+func (opts EncodeOptions) EncodeTOML(value any) ([]byte, error) {
+	var out bytes.Buffer
+
+	encoder := toml.NewEncoder(&out)
+	encoder.Indent = strings.Repeat(" ", opts.indent(defaultTOMLIndent))
+
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// EncodeXML encodes the value to indented XML, see MarshalXML.
+func (opts EncodeOptions) EncodeXML(value any) ([]byte, error) {
+	return MarshalXML(value, strings.Repeat(" ", opts.indent(defaultXMLIndent)))
+}
+
+func (opts EncodeOptions) indent(defaultIndent int) int {
+	if opts.Indent <= 0 {
+		return defaultIndent
+	}
+
+	return opts.Indent
+}
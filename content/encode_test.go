@@ -0,0 +1,64 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeOptions_EncodeJSON(t *testing.T) {
+	value := map[string]any{"name": "<build>", "stages": []string{"test"}}
+
+	t.Run("should encode json with default indentation", func(t *testing.T) {
+		out, err := content.EncodeOptions{}.EncodeJSON(value)
+		require.NoError(t, err)
+		assert.Equal(t, "{\n     \"name\": \"\\u003cbuild\\u003e\",\n     \"stages\": [\n          \"test\"\n     ]\n}", string(out))
+	})
+
+	t.Run("should encode compact json without html escaping", func(t *testing.T) {
+		out, err := content.EncodeOptions{Compact: true, NoHTMLEscape: true}.EncodeJSON(value)
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"<build>","stages":["test"]}`, string(out))
+	})
+
+	t.Run("should encode json with custom indentation", func(t *testing.T) {
+		out, err := content.EncodeOptions{Indent: 2}.EncodeJSON(map[string]string{"name": "build"})
+		require.NoError(t, err)
+		assert.Equal(t, "{\n  \"name\": \"build\"\n}", string(out))
+	})
+}
+
+func TestEncodeOptions_EncodeYAML(t *testing.T) {
+	value := map[string]any{"name": "yes", "stages": []string{"test"}}
+
+	t.Run("should encode yaml with defaults", func(t *testing.T) {
+		out, err := content.EncodeOptions{}.EncodeYAML(value)
+		require.NoError(t, err)
+		assert.Equal(t, "---\nname: \"yes\"\nstages:\n  - test\n", string(out))
+	})
+
+	t.Run("should encode yaml in flow style without document separator", func(t *testing.T) {
+		out, err := content.EncodeOptions{FlowStyle: true, NoDocumentSeparator: true}.EncodeYAML(value)
+		require.NoError(t, err)
+		assert.Equal(t, "{name: \"yes\", stages: [test]}\n", string(out))
+	})
+
+	t.Run("should encode yaml with custom indentation and single quotes", func(t *testing.T) {
+		out, err := content.EncodeOptions{Indent: 4, QuoteStyle: content.QuoteStyleSingle}.EncodeYAML(value)
+		require.NoError(t, err)
+		assert.Equal(t, "---\nname: 'yes'\nstages:\n    - test\n", string(out))
+	})
+
+	t.Run("should error for unknown quote style", func(t *testing.T) {
+		_, err := content.EncodeOptions{QuoteStyle: "backtick"}.EncodeYAML(value)
+		require.EqualError(t, err, "unknown quote style 'backtick', should be one of 'double' or 'single'")
+	})
+}
+
+func TestEncodeOptions_EncodeXML(t *testing.T) {
+	out, err := content.EncodeOptions{Indent: 4}.EncodeXML(map[string]any{"name": "build"})
+	require.NoError(t, err)
+	assert.Equal(t, "<root>\n    <name>build</name>\n</root>", string(out))
+}
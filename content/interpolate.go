@@ -0,0 +1,250 @@
+package content
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// InterpolateOptions holds the options for Interpolate.
+type InterpolateOptions struct {
+	// Variables are the values of the variables, the environment is used when not set.
+	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	// Environment looks up the variables missing in Variables from the environment.
+	Environment bool `json:"environment,omitempty" yaml:"environment,omitempty"`
+	// Keys expands the placeholders in the keys of mappings as well, only the values are expanded by default.
+	Keys bool `json:"keys,omitempty" yaml:"keys,omitempty"`
+	// Strict fails the interpolation when the variables without default are not set.
+	Strict bool `json:"strict,omitempty" yaml:"strict,omitempty"`
+}
+
+// Interpolate expands the placeholders '${VAR}' and '${VAR:-default}' in the string values of the YAML or JSON content,
+// where the default is used when the variable is not set or empty, and '$$' is replaced by a literal '$'.
+// The content is expanded after parsing it, so that the quoted values stay quoted and the comments are retained,
+// while the plain values that would change the structure once expanded, ex: 'a: b', are quoted.
+// The variables that are not set expand to empty string, unless InterpolateOptions.Strict is set.
+func (obj Object) Interpolate(log *logrus.Logger, opts InterpolateOptions) (interpolated Object, err error) {
+	fileType := obj.CheckFileType(log)
+	if fileType != FileTypeJSON && fileType != FileTypeYAML {
+		return "", &errors.CommonError{Message: fmt.Sprintf("interpolation supports only YAML and JSON content, found '%s'", fileType)}
+	}
+
+	log.Debugf("interpolating variables in %s content", fileType)
+
+	// github.com/goccy/go-yaml can produce panics, the content is reported as not interpolated when it does.
+	err = &errors.CommonError{Message: fmt.Sprintf("interpolating variables in %s content failed", fileType)}
+	defer handlePanic(log)
+
+	file, parseErr := parser.ParseBytes([]byte(normalizeContent(string(obj))), parser.ParseComments)
+	if parseErr != nil {
+		return "", &errors.CommonError{Message: fmt.Sprintf("parsing %s content errored with '%v'", fileType, parseErr)}
+	}
+
+	interpolator := &interpolator{opts: opts}
+
+	for _, doc := range file.Docs {
+		if interpolateErr := interpolator.interpolate(doc); interpolateErr != nil {
+			return "", interpolateErr
+		}
+	}
+
+	if len(interpolator.unset) != 0 {
+		return "", &errors.CommonError{Message: fmt.Sprintf("variables are not set: %s", strings.Join(interpolator.unset, "; "))}
+	}
+
+	if fileType == FileTypeYAML {
+		return Object(strings.TrimRight(file.String(), "\n") + "\n"), nil
+	}
+
+	// goccy/go-yaml writes JSON in a single line, which is indented back.
+	value, parseErr := parseOrdered(file.String(), FileTypeYAML)
+	if parseErr != nil {
+		return "", parseErr
+	}
+
+	out, encodeErr := encodeOrderedJSON(value)
+	if encodeErr != nil {
+		return "", encodeErr
+	}
+
+	return Object(out), nil
+}
+
+type interpolator struct {
+	opts  InterpolateOptions
+	unset []string
+}
+
+func (interpolator *interpolator) interpolate(node ast.Node) error {
+	switch typedNode := node.(type) {
+	case *ast.DocumentNode:
+		return interpolator.interpolate(typedNode.Body)
+	case *ast.AnchorNode:
+		return interpolator.interpolate(typedNode.Value)
+	case *ast.TagNode:
+		return interpolator.interpolate(typedNode.Value)
+	case *ast.MappingNode:
+		for _, value := range typedNode.Values {
+			if err := interpolator.interpolate(value); err != nil {
+				return err
+			}
+		}
+	case *ast.MappingValueNode:
+		if interpolator.opts.Keys {
+			if err := interpolator.interpolate(typedNode.Key); err != nil {
+				return err
+			}
+		}
+
+		return interpolator.interpolate(typedNode.Value)
+	case *ast.SequenceNode:
+		for _, value := range typedNode.Values {
+			if err := interpolator.interpolate(value); err != nil {
+				return err
+			}
+		}
+	case *ast.StringNode:
+		return interpolator.interpolateString(typedNode)
+	case *ast.LiteralNode:
+		value, err := interpolator.expand(typedNode.Value.Value, typedNode.Value.Token.Position)
+		if err != nil {
+			return err
+		}
+
+		// literal and folded blocks are written from the original text.
+		origin, err := interpolator.expand(typedNode.Value.Token.Origin, typedNode.Value.Token.Position)
+		if err != nil {
+			return err
+		}
+
+		typedNode.Value.Value, typedNode.Value.Token.Value, typedNode.Value.Token.Origin = value, value, origin
+	}
+
+	return nil
+}
+
+func (interpolator *interpolator) interpolateString(node *ast.StringNode) error {
+	if !strings.Contains(node.Value, "$") {
+		return nil
+	}
+
+	value, err := interpolator.expand(node.Value, node.Token.Position)
+	if err != nil {
+		return err
+	}
+
+	node.Value, node.Token.Value = value, value
+
+	if node.Token.Type != token.SingleQuoteType && node.Token.Type != token.DoubleQuoteType && needsQuoting(value) {
+		node.Token.Type = token.DoubleQuoteType
+	}
+
+	return nil
+}
+
+// expand replaces the placeholders in the value, recording the variables that are not set when strict.
+func (interpolator *interpolator) expand(value string, position *token.Position) (string, error) {
+	var expanded strings.Builder
+
+	for index := 0; index < len(value); index++ {
+		if value[index] != '$' || index+1 == len(value) {
+			expanded.WriteByte(value[index])
+
+			continue
+		}
+
+		switch value[index+1] {
+		case '$':
+			expanded.WriteByte('$')
+			index++
+
+			continue
+		case '{':
+		default:
+			expanded.WriteByte('$')
+
+			continue
+		}
+
+		end := strings.IndexByte(value[index:], '}')
+		if end < 0 {
+			return "", &errors.CommonError{Message: fmt.Sprintf("unterminated placeholder '%s'%s", value[index:], atPosition(position))}
+		}
+
+		placeholder := value[index+2 : index+end]
+		name, defaultValue, hasDefault := strings.Cut(placeholder, ":-")
+
+		if !isVariableName(name) {
+			return "", &errors.CommonError{Message: fmt.Sprintf("invalid variable name in placeholder '${%s}'%s", placeholder, atPosition(position))}
+		}
+
+		variable, ok := interpolator.lookup(name)
+
+		switch {
+		case hasDefault && len(variable) == 0:
+			variable = defaultValue
+		case !ok && interpolator.opts.Strict:
+			if unset := fmt.Sprintf("'%s'%s", name, atPosition(position)); !slices.Contains(interpolator.unset, unset) {
+				interpolator.unset = append(interpolator.unset, unset)
+			}
+		}
+
+		expanded.WriteString(variable)
+		index += end
+	}
+
+	return expanded.String(), nil
+}
+
+func (interpolator *interpolator) lookup(name string) (string, bool) {
+	if value, ok := interpolator.opts.Variables[name]; ok {
+		return value, true
+	}
+
+	if interpolator.opts.Variables == nil || interpolator.opts.Environment {
+		return os.LookupEnv(name)
+	}
+
+	return "", false
+}
+
+func atPosition(position *token.Position) string {
+	if position == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(" at line %d, column %d", position.Line, position.Column)
+}
+
+func isVariableName(name string) bool {
+	if len(name) == 0 || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+
+	for _, character := range name {
+		if character != '_' && (character < 'a' || character > 'z') && (character < 'A' || character > 'Z') && (character < '0' || character > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// needsQuoting checks if the plain value would not be read back as the same string, quoting does not change the meaning of such values.
+func needsQuoting(value string) bool {
+	return len(value) == 0 ||
+		strings.TrimSpace(value) != value ||
+		strings.ContainsAny(value[:1], "?:#&*!|>'\"%@`") ||
+		value == "-" || strings.HasPrefix(value, "- ") ||
+		strings.ContainsAny(value, ",[]{}\n") ||
+		strings.Contains(value, ": ") ||
+		strings.Contains(value, " #") ||
+		strings.HasSuffix(value, ":")
+}
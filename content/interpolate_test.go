@@ -0,0 +1,98 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObject_Interpolate(t *testing.T) {
+	logger := logrus.New()
+	variables := map[string]string{"NAME": "sample", "PORT": "8080", "EMPTY": "", "LABEL": "team: core"}
+
+	t.Run("should expand the placeholders in yaml values retaining the quotes and comments", func(t *testing.T) {
+		obj := content.Object(`# pipeline configuration
+name: ${NAME} # name of the pipeline
+port: ${PORT}
+version: "${PORT}"
+path: '/opt/${NAME}'
+label: ${LABEL}
+mode: ${MODE:-auto}
+fallback: ${EMPTY:-none}
+price: $$5 for ${NAME}
+${NAME}: key
+script: |
+  echo ${NAME}
+  exit 0
+`)
+
+		out, err := obj.Interpolate(logger, content.InterpolateOptions{Variables: variables})
+		require.NoError(t, err)
+		assert.Equal(t, `# pipeline configuration
+name: sample # name of the pipeline
+port: 8080
+version: "8080"
+path: '/opt/sample'
+label: "team: core"
+mode: auto
+fallback: none
+price: $5 for sample
+${NAME}: key
+script: |
+  echo sample
+  exit 0
+`, out.String())
+	})
+
+	t.Run("should expand the placeholders in keys when enabled", func(t *testing.T) {
+		out, err := content.Object("${NAME}: ${PORT}\n").Interpolate(logger, content.InterpolateOptions{Variables: variables, Keys: true})
+		require.NoError(t, err)
+		assert.Equal(t, "sample: 8080\n", out.String())
+	})
+
+	t.Run("should expand the placeholders in json values", func(t *testing.T) {
+		obj := content.Object(`{"name": "${NAME}", "ports": ["${PORT}"], "retries": 2}`)
+
+		out, err := obj.Interpolate(logger, content.InterpolateOptions{Variables: variables})
+		require.NoError(t, err)
+		assert.Equal(t, "{\n     \"name\": \"sample\",\n     \"ports\": [\n          \"8080\"\n     ],\n     \"retries\": 2\n}", out.String())
+	})
+
+	t.Run("should expand the placeholders from the environment", func(t *testing.T) {
+		t.Setenv("COMMON_INTERPOLATE_REGION", "eu")
+
+		out, err := content.Object("region: ${COMMON_INTERPOLATE_REGION}\nname: ${NAME}\n").
+			Interpolate(logger, content.InterpolateOptions{Variables: variables, Environment: true})
+		require.NoError(t, err)
+		assert.Equal(t, "region: eu\nname: sample\n", out.String())
+	})
+
+	t.Run("should expand the variables that are not set to empty string", func(t *testing.T) {
+		out, err := content.Object("name: ${MISSING}\nurl: http://${MISSING}/api\n").Interpolate(logger, content.InterpolateOptions{Variables: variables})
+		require.NoError(t, err)
+		assert.Equal(t, "name: \"\"\nurl: http:///api\n", out.String())
+	})
+
+	t.Run("should fail on the variables that are not set in strict mode", func(t *testing.T) {
+		obj := content.Object("name: ${MISSING}\nmode: ${MODE:-auto}\nurl: http://${HOST}/api\n")
+
+		_, err := obj.Interpolate(logger, content.InterpolateOptions{Variables: variables, Strict: true})
+		assert.EqualError(t, err, "variables are not set: 'MISSING' at line 1, column 7; 'HOST' at line 3, column 6")
+	})
+
+	t.Run("should fail on malformed placeholders", func(t *testing.T) {
+		_, err := content.Object("name: ${NAME\n").Interpolate(logger, content.InterpolateOptions{Variables: variables})
+		assert.EqualError(t, err, "unterminated placeholder '${NAME' at line 1, column 7")
+
+		_, err = content.Object("name: ${1NAME}\n").Interpolate(logger, content.InterpolateOptions{Variables: variables})
+		assert.EqualError(t, err, "invalid variable name in placeholder '${1NAME}' at line 1, column 7")
+	})
+
+	t.Run("should fail to interpolate content other than yaml or json", func(t *testing.T) {
+		_, err := content.Object("<name>${NAME}</name>").Interpolate(logger, content.InterpolateOptions{Variables: variables})
+		assert.EqualError(t, err, "interpolation supports only YAML and JSON content, found 'xml'")
+	})
+}
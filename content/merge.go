@@ -0,0 +1,317 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ListStrategyReplace replaces the list with the list of the overlay, this is the default.
+	ListStrategyReplace = "replace"
+	// ListStrategyAppend appends the elements of the list of the overlay to the list.
+	ListStrategyAppend = "append"
+	// ListStrategyMerge merges the objects in the lists that have the same value for MergeOptions.MergeKey,
+	// the rest of the elements of the overlay are appended.
+	ListStrategyMerge = "merge"
+
+	// PatchKey is the key of the directives in the overlay, ex: '$patch: delete' removes the object from the merged content,
+	// while '$patch: replace' replaces the object instead of merging it.
+	PatchKey = "$patch"
+	// PatchDelete is the directive removing the object from the merged content.
+	PatchDelete = "delete"
+	// PatchReplace is the directive replacing the object instead of merging it.
+	PatchReplace = "replace"
+
+	defaultMergeKey = "name"
+)
+
+// MergeOptions holds the options for Merge.
+type MergeOptions struct {
+	// ListStrategy is the strategy for merging lists, one of ListStrategyReplace, ListStrategyAppend or ListStrategyMerge.
+	ListStrategy string `json:"list_strategy,omitempty" yaml:"list_strategy,omitempty"`
+	// ListStrategies overrides the ListStrategy for the lists at the paths, the path is made of the keys leading to the list
+	// joined by '.', ex: 'pipelines.stages'.
+	ListStrategies map[string]string `json:"list_strategies,omitempty" yaml:"list_strategies,omitempty"`
+	// MergeKey is the key identifying the objects in the lists merged with ListStrategyMerge, defaults to 'name'.
+	MergeKey string `json:"merge_key,omitempty" yaml:"merge_key,omitempty"`
+	// KeepNull sets the null values of the overlay in the merged content, instead of removing the keys.
+	KeepNull bool `json:"keep_null,omitempty" yaml:"keep_null,omitempty"`
+}
+
+// Merge deep merges the objects in order, where the objects that follow overlay the ones before them, and returns the merged content
+// in the format of the first object. The objects could be YAML, JSON or TOML and the order of keys is retained.
+// The keys set to null in an overlay are removed from the merged content, as are the objects with '$patch: delete'.
+// Empty overlays, blank or null, are skipped.
+func Merge(log *logrus.Logger, opts MergeOptions, objects ...Object) (Object, error) {
+	if len(objects) == 0 {
+		return "", &errors.CommonError{Message: "no content passed to merge"}
+	}
+
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+
+	var (
+		merged any
+		format string
+	)
+
+	for index, obj := range objects {
+		if index != 0 && len(strings.TrimSpace(string(obj))) == 0 {
+			log.Debugf("skipping empty content at position %d", index)
+
+			continue
+		}
+
+		fileType := obj.CheckFileType(log)
+		if fileType != FileTypeYAML && fileType != FileTypeJSON && fileType != FileTypeTOML {
+			return "", &errors.CommonError{
+				Message: fmt.Sprintf("cannot merge content of type '%s' at position %d, only YAML, JSON and TOML are supported", fileType, index),
+			}
+		}
+
+		value, err := parseOrdered(normalizeContent(string(obj)), fileType)
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case index == 0:
+			format, merged = fileType, withoutPatches(value)
+		case value == nil:
+			log.Debugf("skipping empty content at position %d", index)
+		default:
+			log.Debugf("merging %s content at position %d", fileType, index)
+
+			if merged, _ = opts.merge(merged, value, ""); merged == nil {
+				merged = yaml.MapSlice{}
+			}
+		}
+	}
+
+	return encodeOrdered(merged, format)
+}
+
+func (opts MergeOptions) validate() error {
+	strategies := map[string]string{"": opts.ListStrategy}
+	for path, strategy := range opts.ListStrategies {
+		strategies[path] = strategy
+	}
+
+	for path, strategy := range strategies {
+		switch strategy {
+		case "", ListStrategyReplace, ListStrategyAppend, ListStrategyMerge:
+		default:
+			return &errors.CommonError{
+				Message: fmt.Sprintf("unknown list strategy '%s' for '%s', should be one of '%s', '%s' or '%s'",
+					strategy, path, ListStrategyReplace, ListStrategyAppend, ListStrategyMerge),
+			}
+		}
+	}
+
+	return nil
+}
+
+// merge merges the overlay into the base, reporting false when the value should be removed from the merged content.
+func (opts MergeOptions) merge(base, overlay any, path string) (any, bool) {
+	if overlay == nil && !opts.KeepNull {
+		return nil, false
+	}
+
+	switch typedOverlay := overlay.(type) {
+	case yaml.MapSlice:
+		switch patch(typedOverlay) {
+		case PatchDelete:
+			return nil, false
+		case PatchReplace:
+			return withoutPatches(typedOverlay), true
+		}
+
+		typedBase, ok := base.(yaml.MapSlice)
+		if !ok {
+			return withoutPatches(typedOverlay), true
+		}
+
+		return opts.mergeMaps(typedBase, typedOverlay, path), true
+	case []any:
+		typedBase, ok := base.([]any)
+		if !ok {
+			return withoutPatches(typedOverlay), true
+		}
+
+		return opts.mergeLists(typedBase, typedOverlay, path), true
+	default:
+		return overlay, true
+	}
+}
+
+func (opts MergeOptions) mergeMaps(base, overlay yaml.MapSlice, path string) yaml.MapSlice {
+	merged := make(yaml.MapSlice, len(base))
+	copy(merged, base)
+
+	for _, item := range overlay {
+		key := fmt.Sprint(item.Key)
+		index := mapIndex(merged, key)
+
+		var existing any
+		if index >= 0 {
+			existing = merged[index].Value
+		}
+
+		value, keep := opts.merge(existing, item.Value, joinPath(path, key))
+
+		switch {
+		case !keep && index >= 0:
+			merged = append(merged[:index], merged[index+1:]...)
+		case !keep:
+		case index >= 0:
+			merged[index].Value = value
+		default:
+			merged = append(merged, yaml.MapItem{Key: item.Key, Value: value})
+		}
+	}
+
+	return merged
+}
+
+func (opts MergeOptions) mergeLists(base, overlay []any, path string) []any {
+	strategy, ok := opts.ListStrategies[path]
+	if !ok {
+		strategy = opts.ListStrategy
+	}
+
+	switch strategy {
+	case ListStrategyAppend:
+		merged := make([]any, 0, len(base)+len(overlay))
+		merged = append(merged, base...)
+
+		return append(merged, withoutPatches(overlay).([]any)...)
+	case ListStrategyMerge:
+		return opts.mergeByKey(base, overlay, path)
+	default:
+		return withoutPatches(overlay).([]any)
+	}
+}
+
+// mergeByKey merges the objects of the lists identified by the merge key, the rest of the elements of the overlay are appended.
+func (opts MergeOptions) mergeByKey(base, overlay []any, path string) []any {
+	mergeKey := opts.MergeKey
+	if len(mergeKey) == 0 {
+		mergeKey = defaultMergeKey
+	}
+
+	merged := make([]any, len(base))
+	copy(merged, base)
+
+	for _, element := range overlay {
+		index := -1
+
+		if object, ok := element.(yaml.MapSlice); ok {
+			if key := mapIndex(object, mergeKey); key >= 0 {
+				index = listIndex(merged, mergeKey, object[key].Value)
+			}
+		}
+
+		if index < 0 {
+			if !deletePatch(element) {
+				merged = append(merged, withoutPatches(element))
+			}
+
+			continue
+		}
+
+		value, keep := opts.merge(merged[index], element, path)
+		if !keep {
+			merged = append(merged[:index], merged[index+1:]...)
+
+			continue
+		}
+
+		merged[index] = value
+	}
+
+	return merged
+}
+
+// patch returns the value of the '$patch' directive of the object.
+func patch(object yaml.MapSlice) string {
+	if index := mapIndex(object, PatchKey); index >= 0 {
+		return fmt.Sprint(object[index].Value)
+	}
+
+	return ""
+}
+
+// deletePatch reports whether the value is an object with '$patch: delete'.
+func deletePatch(value any) bool {
+	object, ok := value.(yaml.MapSlice)
+
+	return ok && patch(object) == PatchDelete
+}
+
+// withoutPatches removes the '$patch' directives from the value, which are not part of the merged content,
+// along with the objects with '$patch: delete' since there is nothing left to delete them from.
+func withoutPatches(value any) any {
+	switch typedValue := value.(type) {
+	case yaml.MapSlice:
+		cleaned := make(yaml.MapSlice, 0, len(typedValue))
+
+		for _, item := range typedValue {
+			if fmt.Sprint(item.Key) == PatchKey || deletePatch(item.Value) {
+				continue
+			}
+
+			cleaned = append(cleaned, yaml.MapItem{Key: item.Key, Value: withoutPatches(item.Value)})
+		}
+
+		return cleaned
+	case []any:
+		cleaned := make([]any, 0, len(typedValue))
+		for _, element := range typedValue {
+			if !deletePatch(element) {
+				cleaned = append(cleaned, withoutPatches(element))
+			}
+		}
+
+		return cleaned
+	default:
+		return value
+	}
+}
+
+func mapIndex(object yaml.MapSlice, key string) int {
+	for index, item := range object {
+		if fmt.Sprint(item.Key) == key {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func listIndex(list []any, mergeKey string, value any) int {
+	for index, element := range list {
+		object, ok := element.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+
+		if key := mapIndex(object, mergeKey); key >= 0 && equalValues(object[key].Value, value) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,149 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mergeBase = `name: sample
+replicas: 1
+labels:
+  team: core
+  tier: backend
+stages:
+  - name: build
+    image: golang
+  - name: test
+    image: golang
+  - name: deploy
+    image: helm
+tags: [base]
+`
+
+func TestMerge(t *testing.T) {
+	logger := logrus.New()
+
+	t.Run("should deep merge the overlay replacing the lists by default", func(t *testing.T) {
+		overlay := content.Object("replicas: 3\nlabels:\n  tier: frontend\n  region: eu\ntags: [prod]\n")
+
+		out, err := content.Merge(logger, content.MergeOptions{}, mergeBase, overlay)
+		require.NoError(t, err)
+		assert.Equal(t, `name: sample
+replicas: 3
+labels:
+  team: core
+  tier: frontend
+  region: eu
+stages:
+  - name: build
+    image: golang
+  - name: test
+    image: golang
+  - name: deploy
+    image: helm
+tags:
+  - prod
+`, out.String())
+	})
+
+	t.Run("should remove the keys set to null and the objects marked for deletion", func(t *testing.T) {
+		overlay := content.Object("labels:\n  tier: null\nstages:\n  - name: test\n    $patch: delete\n  - name: deploy\n    image: kubectl\n  - name: lint\n    image: golangci\n")
+
+		out, err := content.Merge(logger, content.MergeOptions{ListStrategy: content.ListStrategyMerge}, mergeBase, overlay)
+		require.NoError(t, err)
+		assert.Equal(t, `name: sample
+replicas: 1
+labels:
+  team: core
+stages:
+  - name: build
+    image: golang
+  - name: deploy
+    image: kubectl
+  - name: lint
+    image: golangci
+tags:
+  - base
+`, out.String())
+	})
+
+	t.Run("should apply the list strategies by path", func(t *testing.T) {
+		overlay := content.Object(`{"stages": [{"name": "build", "image": "golang:1.25"}], "tags": ["prod"]}`)
+
+		out, err := content.Merge(logger, content.MergeOptions{
+			ListStrategy:   content.ListStrategyAppend,
+			ListStrategies: map[string]string{"stages": content.ListStrategyMerge},
+		}, mergeBase, overlay)
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "stages:\n  - name: build\n    image: golang:1.25\n  - name: test\n")
+		assert.Contains(t, out.String(), "tags:\n  - base\n  - prod\n")
+	})
+
+	t.Run("should replace the objects marked for replacement", func(t *testing.T) {
+		overlay := content.Object("labels:\n  $patch: replace\n  owner: platform\n")
+
+		out, err := content.Merge(logger, content.MergeOptions{}, "labels:\n  team: core\n  tier: backend\n", overlay)
+		require.NoError(t, err)
+		assert.Equal(t, "labels:\n  owner: platform\n", out.String())
+	})
+
+	t.Run("should drop the objects marked for deletion under every list strategy", func(t *testing.T) {
+		for _, strategy := range []string{content.ListStrategyReplace, content.ListStrategyAppend, content.ListStrategyMerge} {
+			out, err := content.Merge(logger, content.MergeOptions{ListStrategy: strategy}, "a:\n  - 1\n  - 2\n", "a:\n  - $patch: delete\n")
+			require.NoError(t, err)
+
+			expected := "a: []\n"
+			if strategy != content.ListStrategyReplace {
+				expected = "a:\n  - 1\n  - 2\n"
+			}
+
+			assert.Equal(t, expected, out.String(), strategy)
+		}
+
+		out, err := content.Merge(logger, content.MergeOptions{}, "name: sample\n", "stages:\n  - name: build\n    cache:\n      $patch: delete\n  - $patch: delete\n")
+		require.NoError(t, err)
+		assert.Equal(t, "name: sample\nstages:\n  - name: build\n", out.String())
+	})
+
+	t.Run("should merge multiple overlays returning the format of the first content", func(t *testing.T) {
+		base := content.Object(`{"name": "sample", "stages": [{"id": 1, "image": "golang"}]}`)
+		first := content.Object("stages:\n  - id: 1\n    image: golang:1.25\n  - id: 2\n    image: helm\n")
+		second := content.Object("name = \"release\"\n")
+
+		out, err := content.Merge(logger, content.MergeOptions{ListStrategy: content.ListStrategyMerge, MergeKey: "id"}, base, first, second)
+		require.NoError(t, err)
+		assert.Equal(t, `{
+     "name": "release",
+     "stages": [
+          {
+               "id": 1,
+               "image": "golang:1.25"
+          },
+          {
+               "id": 2,
+               "image": "helm"
+          }
+     ]
+}`, out.String())
+	})
+
+	t.Run("should skip the empty overlays", func(t *testing.T) {
+		out, err := content.Merge(logger, content.MergeOptions{}, "name: sample\n", "", "  \n", "null\n")
+		require.NoError(t, err)
+		assert.Equal(t, "name: sample\n", out.String())
+	})
+
+	t.Run("should fail on unknown list strategy", func(t *testing.T) {
+		_, err := content.Merge(logger, content.MergeOptions{ListStrategies: map[string]string{"stages": "zip"}}, mergeBase, mergeBase)
+		assert.EqualError(t, err, "unknown list strategy 'zip' for 'stages', should be one of 'replace', 'append' or 'merge'")
+	})
+
+	t.Run("should fail to merge unsupported content", func(t *testing.T) {
+		_, err := content.Merge(logger, content.MergeOptions{}, mergeBase, "<name>sample</name>")
+		assert.EqualError(t, err, "cannot merge content of type 'xml' at position 1, only YAML, JSON and TOML are supported")
+	})
+}
@@ -0,0 +1,993 @@
+package content
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Match is a value matched by Query.
+type Match struct {
+	// Path locates the value in the content, ex: $.stages[0].name. It could be passed to the Editor to update the value.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Pointer is the JSON pointer to the value, ex: /stages/0/name.
+	Pointer string `json:"pointer,omitempty" yaml:"pointer,omitempty"`
+	// Value is the value matched, decoded to maps, lists and scalars.
+	Value any `json:"value,omitempty" yaml:"value,omitempty"`
+	// Line and Column locate the value in the original content, or its key for the values in mappings.
+	// They are zero when it could not be identified, such as for the values reached through aliases.
+	Line   int `json:"line,omitempty" yaml:"line,omitempty"`
+	Column int `json:"column,omitempty" yaml:"column,omitempty"`
+}
+
+// Decode decodes the value matched into v, which should be a pointer, the same way as Object.Decode.
+func (match Match) Decode(v any) error {
+	out, err := yaml.Marshal(match.Value)
+	if err != nil {
+		return &errors.CommonError{Message: fmt.Sprintf("encoding the value at '%s' errored with '%v'", match.Path, err)}
+	}
+
+	if err = yaml.UnmarshalWithOptions(out, v, yaml.UseJSONUnmarshaler()); err != nil {
+		return &errors.CommonError{Message: fmt.Sprintf("decoding the value at '%s' errored with '%v'", match.Path, err)}
+	}
+
+	return nil
+}
+
+// Query evaluates the JSONPath expression against the YAML or JSON content, returning the values matched in the order they appear.
+// The content is parsed with goccy/go-yaml, which locates each match in the original content and selects the members and indexes
+// with its path support, while the selectors it lacks (unions, slices, negative indexes and filters) are evaluated here.
+//
+// Supported expressions are:
+//
+//	$                                   root
+//	.name, ['name'], ["name"]           member selection
+//	[0], [-1], [0,2], [1:3], [::2]      index, union and slice selection
+//	.*, [*]                             wildcards
+//	..name, ..*, ..[0]                  recursive descent
+//	[?(@.price < 10)], [?@.tags]        filters, with ==, !=, <, <=, >, >=, &&, || and !
+//
+// Aliases and the keys merged with '<<' are followed. Only the first document of multi document YAML is queried.
+func (obj Object) Query(log *logrus.Logger, path string) (matches []Match, err error) {
+	fileType := obj.CheckFileType(log)
+	if fileType != FileTypeJSON && fileType != FileTypeYAML {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("query supports only YAML and JSON content, found '%s'", fileType)}
+	}
+
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("evaluating query '%s' against %s content", path, fileType)
+
+	content := normalizeContent(string(obj))
+
+	// github.com/goccy/go-yaml can produce panics, the query is reported as failed when it does.
+	err = &errors.CommonError{Message: fmt.Sprintf("evaluating query '%s' failed", path)}
+	defer handlePanic(log)
+
+	file, parseErr := parser.ParseBytes([]byte(content), 0)
+	if parseErr != nil {
+		return nil, ValidateYAML(log, content)
+	}
+
+	var root any
+	if decodeErr := yaml.UnmarshalWithOptions([]byte(content), &root, yaml.UseOrderedMap()); decodeErr != nil {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("parsing %s content errored with '%v'", fileType, decodeErr)}
+	}
+
+	var body ast.Node
+	if len(file.Docs) != 0 {
+		body = file.Docs[0].Body
+	}
+
+	positions := make(map[string][2]int)
+	collectPositions(body, "", positions)
+
+	evaluation := &queryEvaluation{root: queryNode{node: body}, values: root, anchors: anchorNodes(body)}
+	nodes := evaluation.evaluate(segments, []queryNode{evaluation.root})
+	matches = make([]Match, 0, len(nodes))
+
+	for _, node := range nodes {
+		pointer := node.pointer()
+		match := Match{Path: joinEditPath(node.path), Pointer: pointer, Value: plainValue(evaluation.value(node))}
+
+		if position, ok := positions[pointer]; ok {
+			match.Line, match.Column = position[0], position[1]
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+// queryEvaluation holds the document the query is evaluated against, as parsed to select the nodes and as decoded to read their values.
+type queryEvaluation struct {
+	root    queryNode
+	values  any
+	anchors map[string]ast.Node
+}
+
+// queryNode is a node reached while evaluating the query, along with the path to it.
+type queryNode struct {
+	node ast.Node
+	path []editSegment
+}
+
+func (node queryNode) child(value ast.Node, segment editSegment) queryNode {
+	path := make([]editSegment, len(node.path), len(node.path)+1)
+	copy(path, node.path)
+
+	return queryNode{node: value, path: append(path, segment)}
+}
+
+func (node queryNode) pointer() string {
+	var pointer strings.Builder
+
+	for _, segment := range node.path {
+		if segment.isIndex {
+			pointer.WriteString(fmt.Sprintf("/%d", segment.index))
+		} else {
+			pointer.WriteString("/" + escapePointer(segment.key))
+		}
+	}
+
+	return pointer.String()
+}
+
+// anchorNodes indexes the values of the anchors in the document by their names, so that the aliases could be followed.
+func anchorNodes(body ast.Node) map[string]ast.Node {
+	anchors := make(map[string]ast.Node)
+	if body == nil {
+		return anchors
+	}
+
+	for _, node := range ast.Filter(ast.AnchorType, body) {
+		if anchor, ok := node.(*ast.AnchorNode); ok {
+			anchors[anchor.Name.GetToken().Value] = anchor.Value
+		}
+	}
+
+	return anchors
+}
+
+// resolve returns the node holding the value, following the anchors, tags and aliases.
+// The aliases resolving to themselves resolve to nil.
+func (evaluation *queryEvaluation) resolve(node ast.Node) ast.Node {
+	aliases := make(map[string]bool)
+
+	for {
+		switch typedNode := node.(type) {
+		case *ast.AnchorNode:
+			node = typedNode.Value
+		case *ast.TagNode:
+			node = typedNode.Value
+		case *ast.AliasNode:
+			name := typedNode.Value.GetToken().Value
+			if aliases[name] {
+				return nil
+			}
+
+			aliases[name] = true
+			node = evaluation.anchors[name]
+		default:
+			return node
+		}
+	}
+}
+
+// value returns the decoded value of the node, read by its path from the decoded document where the aliases and merges are resolved.
+func (evaluation *queryEvaluation) value(node queryNode) any {
+	value := evaluation.values
+
+	for _, segment := range node.path {
+		switch typedValue := value.(type) {
+		case yaml.MapSlice:
+			value = nil
+
+			// the keys merged come first in the decoded mapping, so the last one found is the one overriding.
+			for _, item := range typedValue {
+				if fmt.Sprint(item.Key) == segment.key {
+					value = item.Value
+				}
+			}
+		case []any:
+			if !segment.isIndex || segment.index >= len(typedValue) {
+				return nil
+			}
+
+			value = typedValue[segment.index]
+		default:
+			return nil
+		}
+	}
+
+	return value
+}
+
+// selectPath selects the member or the index of the node with the path of goccy/go-yaml, reporting false when it does not exist.
+func (evaluation *queryEvaluation) selectPath(path *yaml.Path, node ast.Node) (ast.Node, bool) {
+	selected, err := path.FilterNode(evaluation.resolve(node))
+
+	return selected, err == nil && selected != nil
+}
+
+// queryEntry is a key of a mapping along with its value.
+type queryEntry struct {
+	key   string
+	value ast.Node
+}
+
+// entries returns the keys of the mapping along with their values in order, where the keys merged with '<<' are overridden by the keys of the mapping.
+func (evaluation *queryEvaluation) entries(node ast.Node) []queryEntry {
+	var values []*ast.MappingValueNode
+
+	switch typedNode := evaluation.resolve(node).(type) {
+	case *ast.MappingNode:
+		values = typedNode.Values
+	case *ast.MappingValueNode:
+		values = []*ast.MappingValueNode{typedNode}
+	default:
+		return nil
+	}
+
+	entries := make([]queryEntry, 0, len(values))
+	indexes := make(map[string]int)
+
+	for _, value := range values {
+		if _, merge := value.Key.(*ast.MergeKeyNode); !merge {
+			key := value.Key.GetToken().Value
+			if stringKey, ok := value.Key.(*ast.StringNode); ok {
+				key = stringKey.Value
+			}
+
+			if index, ok := indexes[key]; ok {
+				entries[index].value = value.Value
+
+				continue
+			}
+
+			indexes[key] = len(entries)
+			entries = append(entries, queryEntry{key: key, value: value.Value})
+
+			continue
+		}
+
+		sources := []ast.Node{value.Value}
+		if sequence, ok := evaluation.resolve(value.Value).(*ast.SequenceNode); ok {
+			sources = sequence.Values
+		}
+
+		for _, source := range sources {
+			for _, entry := range evaluation.entries(source) {
+				if _, ok := indexes[entry.key]; !ok {
+					indexes[entry.key] = len(entries)
+					entries = append(entries, entry)
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// children returns the values of the mapping or the items of the sequence, in order.
+func (evaluation *queryEvaluation) children(node queryNode) []queryNode {
+	if sequence, ok := evaluation.resolve(node.node).(*ast.SequenceNode); ok {
+		children := make([]queryNode, 0, len(sequence.Values))
+		for index, value := range sequence.Values {
+			children = append(children, node.child(value, editSegment{index: index, isIndex: true}))
+		}
+
+		return children
+	}
+
+	entries := evaluation.entries(node.node)
+
+	children := make([]queryNode, 0, len(entries))
+	for _, entry := range entries {
+		children = append(children, node.child(entry.value, editSegment{key: entry.key}))
+	}
+
+	return children
+}
+
+// descendants returns the node followed by all the values nested in it, depth first.
+func (evaluation *queryEvaluation) descendants(node queryNode) []queryNode {
+	return evaluation.descend(node, make(map[ast.Node]bool))
+}
+
+// descend collects the descendants of the node, skipping the values reached again through aliases nested in themselves,
+// which are tracked by the nodes being descended.
+func (evaluation *queryEvaluation) descend(node queryNode, descending map[ast.Node]bool) []queryNode {
+	resolved := evaluation.resolve(node.node)
+	if resolved == nil {
+		return []queryNode{node}
+	}
+
+	if descending[resolved] {
+		return nil
+	}
+
+	descending[resolved] = true
+	defer delete(descending, resolved)
+
+	nodes := []queryNode{node}
+	for _, child := range evaluation.children(node) {
+		nodes = append(nodes, evaluation.descend(child, descending)...)
+	}
+
+	return nodes
+}
+
+type jsonPathSegment struct {
+	descendant bool
+	selectors  []jsonPathSelector
+}
+
+type jsonPathSelector struct {
+	kind       string
+	name       string
+	index      int
+	slice      [3]*int
+	expression filterExpression
+}
+
+const (
+	selectorName     = "name"
+	selectorWildcard = "wildcard"
+	selectorIndex    = "index"
+	selectorSlice    = "slice"
+	selectorFilter   = "filter"
+)
+
+func (evaluation *queryEvaluation) evaluate(segments []jsonPathSegment, nodes []queryNode) []queryNode {
+	for _, segment := range segments {
+		selected := make([]queryNode, 0)
+
+		for _, node := range nodes {
+			targets := []queryNode{node}
+			if segment.descendant {
+				targets = evaluation.descendants(node)
+			}
+
+			for _, target := range targets {
+				for _, selector := range segment.selectors {
+					selected = append(selected, evaluation.selectFrom(selector, target)...)
+				}
+			}
+		}
+
+		nodes = selected
+	}
+
+	return nodes
+}
+
+func (evaluation *queryEvaluation) selectFrom(selector jsonPathSelector, node queryNode) []queryNode {
+	switch selector.kind {
+	case selectorName:
+		if value, ok := evaluation.selectPath((&yaml.PathBuilder{}).Root().Child(selector.name).Build(), node.node); ok {
+			return []queryNode{node.child(value, editSegment{key: selector.name})}
+		}
+
+		// goccy/go-yaml does not select the keys merged with '<<'.
+		for _, entry := range evaluation.entries(node.node) {
+			if entry.key == selector.name {
+				return []queryNode{node.child(entry.value, editSegment{key: selector.name})}
+			}
+		}
+	case selectorWildcard:
+		return evaluation.children(node)
+	case selectorIndex:
+		if sequence, ok := evaluation.resolve(node.node).(*ast.SequenceNode); ok {
+			index := selector.index
+			if index < 0 {
+				index += len(sequence.Values)
+			}
+
+			if index < 0 {
+				return nil
+			}
+
+			if value, ok := evaluation.selectPath((&yaml.PathBuilder{}).Root().Index(uint(index)).Build(), sequence); ok {
+				return []queryNode{node.child(value, editSegment{index: index, isIndex: true})}
+			}
+		}
+	case selectorSlice:
+		if sequence, ok := evaluation.resolve(node.node).(*ast.SequenceNode); ok {
+			selected := make([]queryNode, 0)
+			for _, index := range sliceIndexes(selector.slice, len(sequence.Values)) {
+				selected = append(selected, node.child(sequence.Values[index], editSegment{index: index, isIndex: true}))
+			}
+
+			return selected
+		}
+	case selectorFilter:
+		selected := make([]queryNode, 0)
+
+		for _, child := range evaluation.children(node) {
+			if value, exists := selector.expression(evaluation, child); exists && isQueryTruthy(value) {
+				selected = append(selected, child)
+			}
+		}
+
+		return selected
+	}
+
+	return nil
+}
+
+// sliceIndexes returns the indexes selected by the slice start:end:step, the way Python slices lists.
+func sliceIndexes(slice [3]*int, length int) []int {
+	step := 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+
+	if step == 0 {
+		return nil
+	}
+
+	normalize := func(bound *int, fallback int) int {
+		if bound == nil {
+			return fallback
+		}
+
+		if *bound < 0 {
+			return *bound + length
+		}
+
+		return *bound
+	}
+
+	indexes := make([]int, 0)
+
+	if step > 0 {
+		for index := max(normalize(slice[0], 0), 0); index < min(normalize(slice[1], length), length); index += step {
+			indexes = append(indexes, index)
+		}
+
+		return indexes
+	}
+
+	for index := min(normalize(slice[0], length-1), length-1); index > max(normalize(slice[1], -length-1), -1); index += step {
+		indexes = append(indexes, index)
+	}
+
+	return indexes
+}
+
+// filterExpression evaluates against the document and the current node '@', reporting false when the value does not exist.
+type filterExpression func(evaluation *queryEvaluation, current queryNode) (any, bool)
+
+type jsonPathParser struct {
+	expression string
+	position   int
+}
+
+func parseJSONPath(expression string) ([]jsonPathSegment, error) {
+	jsonPath := &jsonPathParser{expression: strings.TrimSpace(expression)}
+
+	if !jsonPath.consume("$") {
+		return nil, jsonPath.errorf("expression should start with '$'")
+	}
+
+	segments, err := jsonPath.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+
+	if !jsonPath.done() {
+		return nil, jsonPath.errorf("unexpected '%c'", jsonPath.peek())
+	}
+
+	return segments, nil
+}
+
+func (jsonPath *jsonPathParser) done() bool {
+	return jsonPath.position >= len(jsonPath.expression)
+}
+
+func (jsonPath *jsonPathParser) peek() byte {
+	if jsonPath.done() {
+		return 0
+	}
+
+	return jsonPath.expression[jsonPath.position]
+}
+
+func (jsonPath *jsonPathParser) consume(prefix string) bool {
+	if strings.HasPrefix(jsonPath.expression[jsonPath.position:], prefix) {
+		jsonPath.position += len(prefix)
+
+		return true
+	}
+
+	return false
+}
+
+func (jsonPath *jsonPathParser) skipSpaces() {
+	for !jsonPath.done() && unicode.IsSpace(rune(jsonPath.peek())) {
+		jsonPath.position++
+	}
+}
+
+func (jsonPath *jsonPathParser) errorf(format string, args ...any) error {
+	return &errors.CommonError{
+		Message: fmt.Sprintf("invalid query '%s' at position %d: %s", jsonPath.expression, jsonPath.position, fmt.Sprintf(format, args...)),
+	}
+}
+
+func (jsonPath *jsonPathParser) parseSegments() ([]jsonPathSegment, error) {
+	segments := make([]jsonPathSegment, 0)
+
+	for !jsonPath.done() {
+		var segment jsonPathSegment
+
+		switch {
+		case jsonPath.consume(".."):
+			segment.descendant = true
+
+			if jsonPath.peek() == '[' {
+				selectors, err := jsonPath.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+
+				segment.selectors = selectors
+
+				break
+			}
+
+			selector, err := jsonPath.parseShorthand()
+			if err != nil {
+				return nil, err
+			}
+
+			segment.selectors = []jsonPathSelector{selector}
+		case jsonPath.consume("."):
+			selector, err := jsonPath.parseShorthand()
+			if err != nil {
+				return nil, err
+			}
+
+			segment.selectors = []jsonPathSelector{selector}
+		case jsonPath.peek() == '[':
+			selectors, err := jsonPath.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+
+			segment.selectors = selectors
+		default:
+			return segments, nil
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// parseShorthand parses the member name or the wildcard following '.' or '..'.
+func (jsonPath *jsonPathParser) parseShorthand() (jsonPathSelector, error) {
+	if jsonPath.consume("*") {
+		return jsonPathSelector{kind: selectorWildcard}, nil
+	}
+
+	start := jsonPath.position
+	for !jsonPath.done() {
+		character := rune(jsonPath.peek())
+		if !unicode.IsLetter(character) && !unicode.IsDigit(character) && character != '_' && character != '-' && character < 0x80 {
+			break
+		}
+
+		jsonPath.position++
+	}
+
+	if start == jsonPath.position {
+		return jsonPathSelector{}, jsonPath.errorf("expected member name or '*'")
+	}
+
+	return jsonPathSelector{kind: selectorName, name: jsonPath.expression[start:jsonPath.position]}, nil
+}
+
+// parseBracket parses the selectors in brackets, separated by ','.
+func (jsonPath *jsonPathParser) parseBracket() ([]jsonPathSelector, error) {
+	jsonPath.position++
+
+	selectors := make([]jsonPathSelector, 0)
+
+	for {
+		jsonPath.skipSpaces()
+
+		selector, err := jsonPath.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+
+		selectors = append(selectors, selector)
+
+		jsonPath.skipSpaces()
+
+		switch {
+		case jsonPath.consume(","):
+			continue
+		case jsonPath.consume("]"):
+			return selectors, nil
+		default:
+			return nil, jsonPath.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (jsonPath *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch character := jsonPath.peek(); {
+	case character == '\'' || character == '"':
+		name, err := jsonPath.parseString()
+		if err != nil {
+			return jsonPathSelector{}, err
+		}
+
+		return jsonPathSelector{kind: selectorName, name: name}, nil
+	case jsonPath.consume("*"):
+		return jsonPathSelector{kind: selectorWildcard}, nil
+	case jsonPath.consume("?"):
+		jsonPath.skipSpaces()
+
+		expression, err := jsonPath.parseOr()
+		if err != nil {
+			return jsonPathSelector{}, err
+		}
+
+		return jsonPathSelector{kind: selectorFilter, expression: expression}, nil
+	default:
+		return jsonPath.parseIndexOrSlice()
+	}
+}
+
+func (jsonPath *jsonPathParser) parseIndexOrSlice() (jsonPathSelector, error) {
+	var bounds [3]*int
+
+	for part := 0; part < len(bounds); part++ {
+		jsonPath.skipSpaces()
+
+		if number, ok := jsonPath.parseInteger(); ok {
+			bounds[part] = &number
+		}
+
+		jsonPath.skipSpaces()
+
+		if part == 0 && jsonPath.peek() != ':' {
+			if bounds[0] == nil {
+				return jsonPathSelector{}, jsonPath.errorf("expected selector")
+			}
+
+			return jsonPathSelector{kind: selectorIndex, index: *bounds[0]}, nil
+		}
+
+		if !jsonPath.consume(":") {
+			break
+		}
+	}
+
+	return jsonPathSelector{kind: selectorSlice, slice: bounds}, nil
+}
+
+func (jsonPath *jsonPathParser) parseInteger() (int, bool) {
+	start := jsonPath.position
+	if jsonPath.peek() == '-' {
+		jsonPath.position++
+	}
+
+	for !jsonPath.done() && jsonPath.peek() >= '0' && jsonPath.peek() <= '9' {
+		jsonPath.position++
+	}
+
+	number, err := strconv.Atoi(jsonPath.expression[start:jsonPath.position])
+	if err != nil {
+		jsonPath.position = start
+
+		return 0, false
+	}
+
+	return number, true
+}
+
+func (jsonPath *jsonPathParser) parseString() (string, error) {
+	quote := jsonPath.peek()
+	start := jsonPath.position
+	jsonPath.position++
+
+	var value strings.Builder
+
+	for !jsonPath.done() && jsonPath.peek() != quote {
+		if jsonPath.peek() == '\\' && jsonPath.position+1 < len(jsonPath.expression) {
+			jsonPath.position++
+		}
+
+		value.WriteByte(jsonPath.peek())
+		jsonPath.position++
+	}
+
+	if !jsonPath.consume(string(quote)) {
+		jsonPath.position = start
+
+		return "", jsonPath.errorf("unterminated string")
+	}
+
+	return value.String(), nil
+}
+
+func (jsonPath *jsonPathParser) parseOr() (filterExpression, error) {
+	left, err := jsonPath.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for jsonPath.skipSpaces(); jsonPath.consume("||"); jsonPath.skipSpaces() {
+		right, err := jsonPath.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logicalExpression(left, right, false)
+	}
+
+	return left, nil
+}
+
+func (jsonPath *jsonPathParser) parseAnd() (filterExpression, error) {
+	left, err := jsonPath.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for jsonPath.skipSpaces(); jsonPath.consume("&&"); jsonPath.skipSpaces() {
+		right, err := jsonPath.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logicalExpression(left, right, true)
+	}
+
+	return left, nil
+}
+
+func (jsonPath *jsonPathParser) parseUnary() (filterExpression, error) {
+	jsonPath.skipSpaces()
+
+	if jsonPath.peek() == '!' && !strings.HasPrefix(jsonPath.expression[jsonPath.position:], "!=") {
+		jsonPath.position++
+
+		operand, err := jsonPath.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(evaluation *queryEvaluation, current queryNode) (any, bool) {
+			value, exists := operand(evaluation, current)
+
+			return !(exists && isQueryTruthy(value)), true
+		}, nil
+	}
+
+	if jsonPath.consume("(") {
+		expression, err := jsonPath.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		jsonPath.skipSpaces()
+
+		if !jsonPath.consume(")") {
+			return nil, jsonPath.errorf("expected ')'")
+		}
+
+		return expression, nil
+	}
+
+	return jsonPath.parseComparison()
+}
+
+func (jsonPath *jsonPathParser) parseComparison() (filterExpression, error) {
+	left, err := jsonPath.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	jsonPath.skipSpaces()
+
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !jsonPath.consume(operator) {
+			continue
+		}
+
+		jsonPath.skipSpaces()
+
+		right, err := jsonPath.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return comparisonExpression(left, right, operator), nil
+	}
+
+	return left, nil
+}
+
+func (jsonPath *jsonPathParser) parseOperand() (filterExpression, error) {
+	switch character := jsonPath.peek(); {
+	case character == '@' || character == '$':
+		jsonPath.position++
+
+		segments, err := jsonPath.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+
+		relative := character == '@'
+
+		return func(evaluation *queryEvaluation, current queryNode) (any, bool) {
+			start := evaluation.root
+			if relative {
+				start = current
+			}
+
+			nodes := evaluation.evaluate(segments, []queryNode{start})
+			if len(nodes) == 0 {
+				return nil, false
+			}
+
+			return evaluation.value(nodes[0]), true
+		}, nil
+	case character == '\'' || character == '"':
+		value, err := jsonPath.parseString()
+		if err != nil {
+			return nil, err
+		}
+
+		return literalExpression(value), nil
+	case jsonPath.consume("true"):
+		return literalExpression(true), nil
+	case jsonPath.consume("false"):
+		return literalExpression(false), nil
+	case jsonPath.consume("null"):
+		return literalExpression(nil), nil
+	default:
+		start := jsonPath.position
+		for !jsonPath.done() && strings.ContainsRune("-+.0123456789eE", rune(jsonPath.peek())) {
+			jsonPath.position++
+		}
+
+		number, err := strconv.ParseFloat(jsonPath.expression[start:jsonPath.position], 64)
+		if err != nil {
+			jsonPath.position = start
+
+			return nil, jsonPath.errorf("expected path, string, number, boolean or null")
+		}
+
+		return literalExpression(number), nil
+	}
+}
+
+func literalExpression(value any) filterExpression {
+	return func(_ *queryEvaluation, _ queryNode) (any, bool) {
+		return value, true
+	}
+}
+
+func logicalExpression(left, right filterExpression, and bool) filterExpression {
+	return func(evaluation *queryEvaluation, current queryNode) (any, bool) {
+		leftValue, leftExists := left(evaluation, current)
+		if leftTruthy := leftExists && isQueryTruthy(leftValue); leftTruthy != and {
+			return leftTruthy, true
+		}
+
+		rightValue, rightExists := right(evaluation, current)
+
+		return rightExists && isQueryTruthy(rightValue), true
+	}
+}
+
+func comparisonExpression(left, right filterExpression, operator string) filterExpression {
+	return func(evaluation *queryEvaluation, current queryNode) (any, bool) {
+		leftValue, leftExists := left(evaluation, current)
+		rightValue, rightExists := right(evaluation, current)
+
+		if !leftExists || !rightExists {
+			equal := leftExists == rightExists
+
+			return (operator == "==" && equal) || (operator == "!=" && !equal), true
+		}
+
+		switch operator {
+		case "==":
+			return equalValues(plainValue(leftValue), plainValue(rightValue)), true
+		case "!=":
+			return !equalValues(plainValue(leftValue), plainValue(rightValue)), true
+		}
+
+		order, comparable := orderValues(leftValue, rightValue)
+		if !comparable {
+			return false, true
+		}
+
+		switch operator {
+		case "<":
+			return order < 0, true
+		case "<=":
+			return order <= 0, true
+		case ">":
+			return order > 0, true
+		default:
+			return order >= 0, true
+		}
+	}
+}
+
+// orderValues compares two numbers or two strings, other values cannot be ordered.
+func orderValues(left, right any) (int, bool) {
+	leftNumber, leftOK := asNumber(left)
+	rightNumber, rightOK := asNumber(right)
+
+	if leftOK && rightOK {
+		switch {
+		case leftNumber < rightNumber:
+			return -1, true
+		case leftNumber > rightNumber:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	leftString, leftOK := left.(string)
+	rightString, rightOK := right.(string)
+
+	if leftOK && rightOK {
+		return strings.Compare(leftString, rightString), true
+	}
+
+	return 0, false
+}
+
+// isQueryTruthy reports whether the value selects the item in a filter, where the values existing are truthy except false.
+func isQueryTruthy(value any) bool {
+	boolean, ok := value.(bool)
+
+	return !ok || boolean
+}
+
+// plainValue converts yaml.MapSlice to maps, so that the values matched could be used without depending on goccy/go-yaml.
+func plainValue(value any) any {
+	switch typedValue := value.(type) {
+	case yaml.MapSlice:
+		mapping := make(map[string]any, len(typedValue))
+		for _, item := range typedValue {
+			mapping[fmt.Sprint(item.Key)] = plainValue(item.Value)
+		}
+
+		return mapping
+	case []any:
+		list := make([]any, 0, len(typedValue))
+		for _, element := range typedValue {
+			list = append(list, plainValue(element))
+		}
+
+		return list
+	default:
+		return value
+	}
+}
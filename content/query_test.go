@@ -0,0 +1,172 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const queryContent = `name: sample
+stages:
+  - name: build
+    image: golang
+    retries: 2
+  - name: test
+    image: golang
+    tags: [unit, race]
+  - name: deploy
+    image: helm
+    retries: 5
+labels:
+  app.kubernetes.io/name: sample
+`
+
+func queryPaths(matches []content.Match) []string {
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		paths = append(paths, match.Path)
+	}
+
+	return paths
+}
+
+func queryValues(matches []content.Match) []any {
+	values := make([]any, 0, len(matches))
+	for _, match := range matches {
+		values = append(values, match.Value)
+	}
+
+	return values
+}
+
+func TestObject_Query(t *testing.T) {
+	logger := logrus.New()
+	obj := content.Object(queryContent)
+
+	t.Run("should select the members and the indexes with their positions", func(t *testing.T) {
+		matches, err := obj.Query(logger, "$.stages[1].name")
+		require.NoError(t, err)
+		assert.Equal(t, []content.Match{{Path: "$.stages[1].name", Pointer: "/stages/1/name", Value: "test", Line: 6, Column: 5}}, matches)
+
+		matches, err = obj.Query(logger, "$['labels']['app.kubernetes.io/name']")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"$.labels['app.kubernetes.io/name']"}, queryPaths(matches))
+		assert.Equal(t, 13, matches[0].Line)
+
+		matches, err = obj.Query(logger, "$.stages[-1].tags[0]")
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("should select with wildcards, unions and slices", func(t *testing.T) {
+		matches, err := obj.Query(logger, "$.stages[*].name")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"build", "test", "deploy"}, queryValues(matches))
+
+		matches, err = obj.Query(logger, "$.stages[0,2].image")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"golang", "helm"}, queryValues(matches))
+
+		matches, err = obj.Query(logger, "$.stages[1:].name")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"test", "deploy"}, queryValues(matches))
+
+		matches, err = obj.Query(logger, "$.stages[::-2].name")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"deploy", "build"}, queryValues(matches))
+	})
+
+	t.Run("should select recursively", func(t *testing.T) {
+		matches, err := obj.Query(logger, "$..name")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"$.name", "$.stages[0].name", "$.stages[1].name", "$.stages[2].name"}, queryPaths(matches))
+
+		matches, err = obj.Query(logger, "$..tags[*]")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"unit", "race"}, queryValues(matches))
+		assert.Equal(t, [2]int{8, 12}, [2]int{matches[0].Line, matches[0].Column})
+	})
+
+	t.Run("should select with filters", func(t *testing.T) {
+		matches, err := obj.Query(logger, "$.stages[?(@.retries > 1 && @.image == 'golang')].name")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"build"}, queryValues(matches))
+
+		matches, err = obj.Query(logger, "$.stages[?@.tags].name")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"test"}, queryValues(matches))
+
+		matches, err = obj.Query(logger, "$.stages[?(!@.retries || @.name == $.stages[2].name)].name")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"test", "deploy"}, queryValues(matches))
+	})
+
+	t.Run("should follow the aliases and the keys merged", func(t *testing.T) {
+		aliasObj := content.Object("defaults: &defaults\n  image: golang\n  retries: 1\nstages:\n  - <<: *defaults\n    name: build\n    retries: 3\n  - *defaults\n")
+
+		matches, err := aliasObj.Query(logger, "$.stages[*].image")
+		require.NoError(t, err)
+		assert.Equal(t, []content.Match{
+			{Path: "$.stages[0].image", Pointer: "/stages/0/image", Value: "golang"},
+			{Path: "$.stages[1].image", Pointer: "/stages/1/image", Value: "golang"},
+		}, matches)
+
+		matches, err = aliasObj.Query(logger, "$.stages[?(@.retries > 2)].name")
+		require.NoError(t, err)
+		assert.Equal(t, []content.Match{{Path: "$.stages[0].name", Pointer: "/stages/0/name", Value: "build", Line: 6, Column: 5}}, matches)
+
+		matches, err = aliasObj.Query(logger, "$.stages[0].*")
+		require.NoError(t, err)
+		assert.Equal(t, []any{"golang", uint64(3), "build"}, queryValues(matches))
+	})
+
+	t.Run("should stop descending at the aliases nested in their anchors", func(t *testing.T) {
+		cyclicObj := content.Object("a: &a\n  b: *a\n")
+
+		matches, err := cyclicObj.Query(logger, "$..b")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"$.a.b"}, queryPaths(matches))
+
+		matches, err = cyclicObj.Query(logger, "$..*")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"$.a", "$.a.b"}, queryPaths(matches))
+	})
+
+	t.Run("should query json returning typed values", func(t *testing.T) {
+		jsonObj := content.Object(`{"stages": [{"name": "build", "retries": 2}, {"name": "test", "retries": 0}]}`)
+
+		matches, err := jsonObj.Query(logger, "$.stages[?(@.retries >= 1)]")
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "/stages/0", matches[0].Pointer)
+		assert.Equal(t, map[string]any{"name": "build", "retries": uint64(2)}, matches[0].Value)
+		assert.Equal(t, 1, matches[0].Line)
+
+		var stage struct {
+			Name    string `json:"name"`
+			Retries int    `json:"retries"`
+		}
+		require.NoError(t, matches[0].Decode(&stage))
+		assert.Equal(t, "build", stage.Name)
+		assert.Equal(t, 2, stage.Retries)
+	})
+
+	t.Run("should fail on invalid queries", func(t *testing.T) {
+		_, err := obj.Query(logger, "stages[0]")
+		assert.EqualError(t, err, "invalid query 'stages[0]' at position 0: expression should start with '$'")
+
+		_, err = obj.Query(logger, "$.stages[0")
+		assert.EqualError(t, err, "invalid query '$.stages[0' at position 10: expected ',' or ']'")
+
+		_, err = obj.Query(logger, "$.stages[?(@.name == )]")
+		assert.EqualError(t, err, "invalid query '$.stages[?(@.name == )]' at position 21: expected path, string, number, boolean or null")
+	})
+
+	t.Run("should fail to query unsupported content", func(t *testing.T) {
+		_, err := content.Object("name = \"sample\"\n").Query(logger, "$.name")
+		assert.EqualError(t, err, "query supports only YAML and JSON content, found 'toml'")
+	})
+}
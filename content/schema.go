@@ -0,0 +1,705 @@
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Schema is a JSON Schema used to validate the content with ValidateSchema.
+// A subset of draft 2020-12 is supported: type, enum, const, properties, required, additionalProperties, patternProperties,
+// minProperties, maxProperties, items, prefixItems, minItems, maxItems, uniqueItems, minLength, maxLength, pattern, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, multipleOf, allOf, anyOf, oneOf, not and local $ref to $defs or definitions.
+// The rest of the keywords are ignored.
+type Schema struct {
+	root     any
+	patterns map[string]*regexp.Regexp
+}
+
+// ParseSchema parses the JSON Schema, which could be written in JSON or YAML.
+func ParseSchema(schema []byte) (*Schema, error) {
+	var root any
+	if err := yaml.Unmarshal(schema, &root); err != nil {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("parsing schema errored with '%v'", err)}
+	}
+
+	switch root.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, &errors.CommonError{Message: "schema should be an object or a boolean"}
+	}
+
+	parsed := &Schema{root: root, patterns: make(map[string]*regexp.Regexp)}
+	if err := parsed.compilePatterns(root); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+// Violation is a part of the content that does not conform to the schema.
+type Violation struct {
+	// Path is the JSON pointer to the value violating the schema, ex: /pipelines/0/name. The root is an empty string.
+	Path string `json:"path" yaml:"path"`
+	// Keyword is the schema keyword that is violated, ex: required.
+	Keyword string `json:"keyword,omitempty" yaml:"keyword,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Document is the index of the YAML document holding the value, starting at 0.
+	Document int `json:"document,omitempty" yaml:"document,omitempty"`
+	// Line and Column locate the value in the original content, they are zero when it could not be identified.
+	Line   int `json:"line,omitempty" yaml:"line,omitempty"`
+	Column int `json:"column,omitempty" yaml:"column,omitempty"`
+}
+
+func (violation Violation) String() string {
+	path := violation.Path
+	if len(path) == 0 {
+		path = "/"
+	}
+
+	if violation.Line == 0 {
+		return fmt.Sprintf("%s: %s", path, violation.Message)
+	}
+
+	return fmt.Sprintf("%s (line %d, column %d): %s", path, violation.Line, violation.Column, violation.Message)
+}
+
+// SchemaError holds every violation found by ValidateSchema.
+type SchemaError struct {
+	Violations []Violation
+}
+
+// Error implements error.
+func (e *SchemaError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+
+	return fmt.Sprintf("content does not conform to the schema: %s", strings.Join(messages, "; "))
+}
+
+// ValidateSchema validates the YAML or JSON content against the schema, returning *SchemaError listing all the violations.
+// Every document of multi document YAML is validated, skipping the documents without content, ex: following a trailing '---'.
+// The violations are located by JSON pointer paths and the index of their document, along with their line numbers in the original content.
+func (obj Object) ValidateSchema(log *logrus.Logger, schema *Schema) (err error) {
+	fileType := obj.CheckFileType(log)
+	if fileType != FileTypeJSON && fileType != FileTypeYAML {
+		return &errors.CommonError{Message: fmt.Sprintf("schema validation supports only YAML and JSON content, found '%s'", fileType)}
+	}
+
+	log.Debugf("validating %s content against the schema", fileType)
+
+	content := normalizeContent(string(obj))
+
+	// github.com/goccy/go-yaml can produce panics, the content is reported as not validated when it does.
+	err = &errors.CommonError{Message: fmt.Sprintf("parsing %s content for schema validation failed", fileType)}
+	defer handlePanic(log)
+
+	file, parseErr := parser.ParseBytes([]byte(content), 0)
+	if parseErr != nil {
+		return ValidateYAML(log, content)
+	}
+
+	validator := &schemaValidator{schema: schema, refs: &schemaRefs{resolving: make(map[[2]string]bool)}}
+
+	for index, doc := range file.Docs {
+		if doc == nil || doc.Body == nil {
+			continue
+		}
+
+		if validateErr := validator.validateDocument(index, doc.Body); validateErr != nil {
+			return validateErr
+		}
+	}
+
+	if len(validator.violations) == 0 {
+		return nil
+	}
+
+	return &SchemaError{Violations: validator.violations}
+}
+
+// collectPositions indexes the line and column of every value in the document by its JSON pointer, keys of mappings locate their values.
+func collectPositions(node ast.Node, pointer string, positions map[string][2]int) {
+	if node == nil {
+		return
+	}
+
+	if _, ok := positions[pointer]; !ok {
+		if start := nodeToken(node); start != nil && start.Position != nil {
+			positions[pointer] = [2]int{start.Position.Line, start.Position.Column}
+		}
+	}
+
+	switch typedNode := node.(type) {
+	case *ast.DocumentNode:
+		collectPositions(typedNode.Body, pointer, positions)
+	case *ast.AnchorNode:
+		collectPositions(typedNode.Value, pointer, positions)
+	case *ast.TagNode:
+		collectPositions(typedNode.Value, pointer, positions)
+	case *ast.MappingNode:
+		for _, value := range typedNode.Values {
+			collectPositions(value, pointer, positions)
+		}
+	case *ast.MappingValueNode:
+		childPointer := pointer + "/" + escapePointer(typedNode.Key.GetToken().Value)
+		collectPositions(typedNode.Key, childPointer, positions)
+		collectPositions(typedNode.Value, childPointer, positions)
+	case *ast.SequenceNode:
+		for index, value := range typedNode.Values {
+			collectPositions(value, fmt.Sprintf("%s/%d", pointer, index), positions)
+		}
+	}
+}
+
+// nodeToken returns the first token of the node, the token of block mappings is the ':' following the first key otherwise.
+func nodeToken(node ast.Node) *token.Token {
+	switch typedNode := node.(type) {
+	case *ast.MappingValueNode:
+		return nodeToken(typedNode.Key)
+	case *ast.MappingNode:
+		if typedNode.IsFlowStyle || len(typedNode.Values) == 0 {
+			return typedNode.GetToken()
+		}
+
+		return nodeToken(typedNode.Values[0])
+	default:
+		return node.GetToken()
+	}
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+type schemaValidator struct {
+	schema     *Schema
+	violations []Violation
+	refs       *schemaRefs
+}
+
+// schemaRefs tracks the references being resolved along with the pointers of the values validated against them,
+// it is shared with the nested validators so that the circular references are found before they exhaust the stack.
+type schemaRefs struct {
+	resolving map[[2]string]bool
+	circular  error
+}
+
+// validateDocument validates the document at the index against the root of the schema, locating its violations in the content.
+func (validator *schemaValidator) validateDocument(index int, body ast.Node) error {
+	var instance any
+	if err := yaml.NodeToValue(body, &instance); err != nil {
+		return &errors.CommonError{Message: fmt.Sprintf("parsing document %d errored with '%v'", index, err)}
+	}
+
+	start := len(validator.violations)
+	validator.validate(validator.schema.root, instance, "")
+
+	if validator.refs.circular != nil {
+		return validator.refs.circular
+	}
+
+	positions := make(map[string][2]int)
+	collectPositions(body, "", positions)
+
+	for violationIndex := start; violationIndex < len(validator.violations); violationIndex++ {
+		violation := &validator.violations[violationIndex]
+		violation.Document = index
+
+		if position, ok := positions[violation.Path]; ok {
+			violation.Line, violation.Column = position[0], position[1]
+		}
+	}
+
+	return nil
+}
+
+func (validator *schemaValidator) fail(pointer, keyword, format string, args ...any) {
+	validator.violations = append(validator.violations, Violation{Path: pointer, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+// valid reports whether the instance conforms to the schema without recording the violations, used by anyOf, oneOf and not.
+func (validator *schemaValidator) valid(schema, instance any, pointer string) bool {
+	nested := &schemaValidator{schema: validator.schema, refs: validator.refs}
+	nested.validate(schema, instance, pointer)
+
+	return len(nested.violations) == 0
+}
+
+// validate records the violations of the instance against the schema, keyword by keyword.
+func (validator *schemaValidator) validate(schema, instance any, pointer string) {
+	if validator.refs.circular != nil {
+		return
+	}
+
+	if allowed, ok := schema.(bool); ok {
+		if !allowed {
+			validator.fail(pointer, "false", "no value is allowed")
+		}
+
+		return
+	}
+
+	keywords, ok := schema.(map[string]any)
+	if !ok {
+		return
+	}
+
+	validator.validateRef(keywords, instance, pointer)
+
+	if !validator.validateType(keywords, instance, pointer) {
+		return
+	}
+
+	validator.validateEnum(keywords, instance, pointer)
+	validator.validateComposition(keywords, instance, pointer)
+
+	switch typedInstance := instance.(type) {
+	case map[string]any:
+		validator.validateObject(keywords, typedInstance, pointer)
+	case []any:
+		validator.validateArray(keywords, typedInstance, pointer)
+	case string:
+		validator.validateString(keywords, typedInstance, pointer)
+	case bool, nil:
+	default:
+		if number, ok := asNumber(instance); ok {
+			validator.validateNumber(keywords, number, pointer)
+		}
+	}
+}
+
+// validateRef validates the instance against the schema referenced by $ref.
+func (validator *schemaValidator) validateRef(keywords map[string]any, instance any, pointer string) {
+	ref, ok := keywords["$ref"].(string)
+	if !ok {
+		return
+	}
+
+	resolved, err := validator.schema.resolve(ref)
+	if err != nil {
+		validator.fail(pointer, "$ref", "%v", err)
+
+		return
+	}
+
+	// resolving the same reference for the same value again would never end.
+	key := [2]string{ref, pointer}
+	if validator.refs.resolving[key] {
+		validator.refs.circular = &errors.CommonError{Message: fmt.Sprintf("schema reference '%s' is circular", ref)}
+
+		return
+	}
+
+	validator.refs.resolving[key] = true
+	defer delete(validator.refs.resolving, key)
+
+	validator.validate(resolved, instance, pointer)
+}
+
+// validateType reports whether the instance is of the type, the rest of the keywords are not validated when it is not.
+func (validator *schemaValidator) validateType(keywords map[string]any, instance any, pointer string) bool {
+	expected, ok := keywords["type"]
+	if !ok || matchesType(expected, instance) {
+		return true
+	}
+
+	validator.fail(pointer, "type", "expected %s, but got %s", typeNames(expected), schemaTypeName(instance))
+
+	return false
+}
+
+// validateEnum validates the instance against the values of enum and const.
+func (validator *schemaValidator) validateEnum(keywords map[string]any, instance any, pointer string) {
+	if enum, ok := keywords["enum"].([]any); ok && !containsValue(enum, instance) {
+		validator.fail(pointer, "enum", "value must be one of %s", marshalValue(enum))
+	}
+
+	if constant, ok := keywords["const"]; ok && !equalValues(constant, instance) {
+		validator.fail(pointer, "const", "value must be %s", marshalValue(constant))
+	}
+}
+
+// validateComposition validates the instance against the schemas of allOf, anyOf, oneOf and not.
+func (validator *schemaValidator) validateComposition(keywords map[string]any, instance any, pointer string) {
+	for _, subSchema := range asList(keywords["allOf"]) {
+		validator.validate(subSchema, instance, pointer)
+	}
+
+	if anyOf := asList(keywords["anyOf"]); len(anyOf) != 0 && validator.countValid(anyOf, instance, pointer) == 0 {
+		validator.fail(pointer, "anyOf", "value does not match any of the schemas in anyOf")
+	}
+
+	if oneOf := asList(keywords["oneOf"]); len(oneOf) != 0 {
+		if matched := validator.countValid(oneOf, instance, pointer); matched != 1 {
+			validator.fail(pointer, "oneOf", "value matches %d of the schemas in oneOf, expected exactly one", matched)
+		}
+	}
+
+	if not, ok := keywords["not"]; ok && validator.valid(not, instance, pointer) {
+		validator.fail(pointer, "not", "value must not match the schema in not")
+	}
+}
+
+// countValid returns the number of schemas the instance conforms to.
+func (validator *schemaValidator) countValid(schemas []any, instance any, pointer string) int {
+	matched := 0
+
+	for _, subSchema := range schemas {
+		if validator.valid(subSchema, instance, pointer) {
+			matched++
+		}
+	}
+
+	return matched
+}
+
+// validateObject validates the object against required, minProperties and maxProperties, and every property against its schema.
+func (validator *schemaValidator) validateObject(keywords, object map[string]any, pointer string) {
+	for _, required := range asList(keywords["required"]) {
+		if _, ok := object[fmt.Sprint(required)]; !ok {
+			validator.fail(pointer, "required", "missing required property '%v'", required)
+		}
+	}
+
+	if minimum, ok := asNumber(keywords["minProperties"]); ok && float64(len(object)) < minimum {
+		validator.fail(pointer, "minProperties", "expected at least %v properties, but got %d", minimum, len(object))
+	}
+
+	if maximum, ok := asNumber(keywords["maxProperties"]); ok && float64(len(object)) > maximum {
+		validator.fail(pointer, "maxProperties", "expected at most %v properties, but got %d", maximum, len(object))
+	}
+
+	for _, key := range sortedStringKeys(object) {
+		validator.validateProperty(keywords, key, object[key], pointer+"/"+escapePointer(key))
+	}
+}
+
+// validateProperty validates the property against the schemas of properties and patternProperties matching its key,
+// or against additionalProperties when none of them match.
+func (validator *schemaValidator) validateProperty(keywords map[string]any, key string, value any, pointer string) {
+	matched := false
+
+	if properties, ok := keywords["properties"].(map[string]any); ok {
+		if propertySchema, ok := properties[key]; ok {
+			matched = true
+
+			validator.validate(propertySchema, value, pointer)
+		}
+	}
+
+	patternProperties, _ := keywords["patternProperties"].(map[string]any)
+	for _, pattern := range sortedStringKeys(patternProperties) {
+		if validator.schema.patterns[pattern].MatchString(key) {
+			matched = true
+
+			validator.validate(patternProperties[pattern], value, pointer)
+		}
+	}
+
+	additionalProperties, hasAdditional := keywords["additionalProperties"]
+	if matched || !hasAdditional {
+		return
+	}
+
+	if allowed, ok := additionalProperties.(bool); ok && !allowed {
+		validator.fail(pointer, "additionalProperties", "additional property '%s' is not allowed", key)
+
+		return
+	}
+
+	validator.validate(additionalProperties, value, pointer)
+}
+
+func (validator *schemaValidator) validateArray(keywords map[string]any, array []any, pointer string) {
+	if minimum, ok := asNumber(keywords["minItems"]); ok && float64(len(array)) < minimum {
+		validator.fail(pointer, "minItems", "expected at least %v items, but got %d", minimum, len(array))
+	}
+
+	if maximum, ok := asNumber(keywords["maxItems"]); ok && float64(len(array)) > maximum {
+		validator.fail(pointer, "maxItems", "expected at most %v items, but got %d", maximum, len(array))
+	}
+
+	if unique, ok := keywords["uniqueItems"].(bool); ok && unique {
+		for index := range array {
+			if containsValue(array[:index], array[index]) {
+				validator.fail(fmt.Sprintf("%s/%d", pointer, index), "uniqueItems", "items must be unique, duplicate of an earlier item")
+			}
+		}
+	}
+
+	prefixItems := asList(keywords["prefixItems"])
+	items, hasItems := keywords["items"]
+
+	for index, item := range array {
+		itemPointer := fmt.Sprintf("%s/%d", pointer, index)
+
+		switch {
+		case index < len(prefixItems):
+			validator.validate(prefixItems[index], item, itemPointer)
+		case hasItems:
+			validator.validate(items, item, itemPointer)
+		}
+	}
+}
+
+func (validator *schemaValidator) validateString(keywords map[string]any, value, pointer string) {
+	length := utf8.RuneCountInString(value)
+
+	if minimum, ok := asNumber(keywords["minLength"]); ok && float64(length) < minimum {
+		validator.fail(pointer, "minLength", "expected at least %v characters, but got %d", minimum, length)
+	}
+
+	if maximum, ok := asNumber(keywords["maxLength"]); ok && float64(length) > maximum {
+		validator.fail(pointer, "maxLength", "expected at most %v characters, but got %d", maximum, length)
+	}
+
+	if pattern, ok := keywords["pattern"].(string); ok && !validator.schema.patterns[pattern].MatchString(value) {
+		validator.fail(pointer, "pattern", "value '%s' does not match pattern '%s'", value, pattern)
+	}
+}
+
+func (validator *schemaValidator) validateNumber(keywords map[string]any, value float64, pointer string) {
+	if minimum, ok := asNumber(keywords["minimum"]); ok && value < minimum {
+		validator.fail(pointer, "minimum", "value %v is less than minimum %v", value, minimum)
+	}
+
+	if maximum, ok := asNumber(keywords["maximum"]); ok && value > maximum {
+		validator.fail(pointer, "maximum", "value %v is greater than maximum %v", value, maximum)
+	}
+
+	if minimum, ok := asNumber(keywords["exclusiveMinimum"]); ok && value <= minimum {
+		validator.fail(pointer, "exclusiveMinimum", "value %v should be greater than %v", value, minimum)
+	}
+
+	if maximum, ok := asNumber(keywords["exclusiveMaximum"]); ok && value >= maximum {
+		validator.fail(pointer, "exclusiveMaximum", "value %v should be less than %v", value, maximum)
+	}
+
+	if divisor, ok := asNumber(keywords["multipleOf"]); ok && divisor > 0 {
+		if quotient := value / divisor; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			validator.fail(pointer, "multipleOf", "value %v is not a multiple of %v", value, divisor)
+		}
+	}
+}
+
+// resolve returns the schema referenced by the local JSON pointer, ex: #/$defs/stage.
+func (schema *Schema) resolve(ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("unsupported reference '%s', only local references are supported", ref)}
+	}
+
+	current := schema.root
+
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch typedCurrent := current.(type) {
+		case map[string]any:
+			next, ok := typedCurrent[token]
+			if !ok {
+				return nil, &errors.CommonError{Message: fmt.Sprintf("reference '%s' could not be resolved", ref)}
+			}
+
+			current = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(typedCurrent) {
+				return nil, &errors.CommonError{Message: fmt.Sprintf("reference '%s' could not be resolved", ref)}
+			}
+
+			current = typedCurrent[index]
+		default:
+			return nil, &errors.CommonError{Message: fmt.Sprintf("reference '%s' could not be resolved", ref)}
+		}
+	}
+
+	return current, nil
+}
+
+// compilePatterns compiles the regular expressions of pattern and patternProperties across the schema.
+func (schema *Schema) compilePatterns(node any) error {
+	switch typedNode := node.(type) {
+	case map[string]any:
+		patterns := make([]string, 0)
+		if pattern, ok := typedNode["pattern"].(string); ok {
+			patterns = append(patterns, pattern)
+		}
+
+		if patternProperties, ok := typedNode["patternProperties"].(map[string]any); ok {
+			patterns = append(patterns, sortedStringKeys(patternProperties)...)
+		}
+
+		for _, pattern := range patterns {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return &errors.CommonError{Message: fmt.Sprintf("invalid pattern '%s' in schema: %v", pattern, err)}
+			}
+
+			schema.patterns[pattern] = compiled
+		}
+
+		for _, key := range sortedStringKeys(typedNode) {
+			if err := schema.compilePatterns(typedNode[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, element := range typedNode {
+			if err := schema.compilePatterns(element); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchesType(expected, instance any) bool {
+	for _, typeName := range asList(expected) {
+		switch name := fmt.Sprint(typeName); {
+		case name == schemaTypeName(instance):
+			return true
+		case name == "number" && schemaTypeName(instance) == "integer":
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeNames(expected any) string {
+	names := make([]string, 0)
+	for _, typeName := range asList(expected) {
+		names = append(names, fmt.Sprint(typeName))
+	}
+
+	return strings.Join(names, " or ")
+}
+
+// schemaTypeName returns the JSON Schema type of the value, where numbers without fraction are integers.
+func schemaTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+
+	if number, ok := asNumber(value); ok {
+		if number == math.Trunc(number) {
+			return "integer"
+		}
+
+		return "number"
+	}
+
+	return reflect.TypeOf(value).String()
+}
+
+// asList returns the value as list, single values are wrapped in a list.
+func asList(value any) []any {
+	switch typedValue := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		return typedValue
+	default:
+		return []any{typedValue}
+	}
+}
+
+func asNumber(value any) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case uint64:
+		return float64(number), true
+	case float64:
+		return number, true
+	default:
+		return 0, false
+	}
+}
+
+func containsValue(values []any, value any) bool {
+	for _, element := range values {
+		if equalValues(element, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equalValues compares the values structurally, numbers are compared by their value irrespective of their type.
+func equalValues(left, right any) bool {
+	leftNumber, leftOK := asNumber(left)
+	rightNumber, rightOK := asNumber(right)
+
+	if leftOK || rightOK {
+		return leftOK && rightOK && leftNumber == rightNumber
+	}
+
+	switch typedLeft := left.(type) {
+	case map[string]any:
+		typedRight, ok := right.(map[string]any)
+		if !ok || len(typedLeft) != len(typedRight) {
+			return false
+		}
+
+		for key, value := range typedLeft {
+			if rightValue, ok := typedRight[key]; !ok || !equalValues(value, rightValue) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		typedRight, ok := right.([]any)
+		if !ok || len(typedLeft) != len(typedRight) {
+			return false
+		}
+
+		for index := range typedLeft {
+			if !equalValues(typedLeft[index], typedRight[index]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return left == right
+	}
+}
+
+func marshalValue(value any) string {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(out)
+}
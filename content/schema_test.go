@@ -0,0 +1,197 @@
+package content_test
+
+import (
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pipelineSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["name", "stages"],
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string", "pattern": "^[a-z-]+$"},
+    "retries": {"type": "integer", "minimum": 0, "maximum": 5},
+    "stages": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/stage"}}
+  },
+  "$defs": {
+    "stage": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "mode": {"enum": ["auto", "manual"]}
+      }
+    }
+  }
+}`
+
+func TestObject_ValidateSchema(t *testing.T) {
+	logger := logrus.New()
+
+	schema, err := content.ParseSchema([]byte(pipelineSchema))
+	require.NoError(t, err)
+
+	t.Run("should validate the yaml content conforming to the schema", func(t *testing.T) {
+		obj := content.Object("name: sample\nretries: 2\nstages:\n  - name: build\n    mode: auto\n")
+
+		assert.NoError(t, obj.ValidateSchema(logger, schema))
+	})
+
+	t.Run("should report all the violations of yaml content with their lines", func(t *testing.T) {
+		obj := content.Object("name: Sample\nretries: 7\nowner: me\nstages:\n  - mode: auto\n  - name: test\n    mode: never\n")
+
+		err = obj.ValidateSchema(logger, schema)
+
+		var schemaError *content.SchemaError
+		require.ErrorAs(t, err, &schemaError)
+		assert.Equal(t, []content.Violation{
+			{Path: "/name", Keyword: "pattern", Message: "value 'Sample' does not match pattern '^[a-z-]+$'", Line: 1, Column: 1},
+			{Path: "/owner", Keyword: "additionalProperties", Message: "additional property 'owner' is not allowed", Line: 3, Column: 1},
+			{Path: "/retries", Keyword: "maximum", Message: "value 7 is greater than maximum 5", Line: 2, Column: 1},
+			{Path: "/stages/0", Keyword: "required", Message: "missing required property 'name'", Line: 5, Column: 5},
+			{Path: "/stages/1/mode", Keyword: "enum", Message: `value must be one of ["auto","manual"]`, Line: 7, Column: 5},
+		}, schemaError.Violations)
+	})
+
+	t.Run("should report the violations of json content with their lines", func(t *testing.T) {
+		obj := content.Object("{\n  \"name\": \"sample\",\n  \"stages\": []\n}")
+
+		err = obj.ValidateSchema(logger, schema)
+		assert.EqualError(t, err, "content does not conform to the schema: /stages (line 3, column 3): expected at least 1 items, but got 0")
+	})
+
+	t.Run("should report the type mismatch at the root", func(t *testing.T) {
+		obj := content.Object("- name: sample\n")
+
+		err = obj.ValidateSchema(logger, schema)
+		assert.EqualError(t, err, "content does not conform to the schema: / (line 1, column 1): expected object, but got array")
+	})
+
+	t.Run("should validate every document of multi document yaml", func(t *testing.T) {
+		obj := content.Object("name: sample\nstages:\n  - name: build\n---\nname: other\nretries: 9\nstages:\n  - name: test\n---\n")
+
+		err = obj.ValidateSchema(logger, schema)
+
+		var schemaError *content.SchemaError
+		require.ErrorAs(t, err, &schemaError)
+		assert.Equal(t, []content.Violation{
+			{Path: "/retries", Keyword: "maximum", Message: "value 9 is greater than maximum 5", Document: 1, Line: 6, Column: 1},
+		}, schemaError.Violations)
+	})
+
+	t.Run("should fail to validate content other than yaml or json", func(t *testing.T) {
+		obj := content.Object("name,stage\nsample,build\nother,test\n")
+
+		err = obj.ValidateSchema(logger, schema)
+		assert.EqualError(t, err, "schema validation supports only YAML and JSON content, found 'csv'")
+	})
+}
+
+func TestObject_ValidateSchemaCombinators(t *testing.T) {
+	logger := logrus.New()
+
+	schema, err := content.ParseSchema([]byte(`
+type: object
+properties:
+  port:
+    oneOf:
+      - type: integer
+        multipleOf: 2
+      - type: integer
+        minimum: 1000
+  tags:
+    type: array
+    uniqueItems: true
+    prefixItems:
+      - const: primary
+    items:
+      type: string
+      maxLength: 5
+  labels:
+    patternProperties:
+      "^x-":
+        type: string
+    additionalProperties:
+      not:
+        type: string
+`))
+	require.NoError(t, err)
+
+	t.Run("should validate the content matching the combinators", func(t *testing.T) {
+		obj := content.Object("port: 8\ntags: [primary, web]\nlabels:\n  x-team: core\n  count: 2\n")
+
+		assert.NoError(t, obj.ValidateSchema(logger, schema))
+	})
+
+	t.Run("should report the violations of the combinators", func(t *testing.T) {
+		obj := content.Object("port: 1002\ntags: [secondary, database, database]\nlabels:\n  x-team: 1\n  owner: me\n")
+
+		err = obj.ValidateSchema(logger, schema)
+
+		var schemaError *content.SchemaError
+		require.ErrorAs(t, err, &schemaError)
+
+		paths := make([]string, 0, len(schemaError.Violations))
+		keywords := make([]string, 0, len(schemaError.Violations))
+
+		for _, violation := range schemaError.Violations {
+			paths = append(paths, violation.Path)
+			keywords = append(keywords, violation.Keyword)
+		}
+
+		assert.Equal(t, []string{"/labels/owner", "/labels/x-team", "/port", "/tags/2", "/tags/0", "/tags/1", "/tags/2"}, paths)
+		assert.Equal(t, []string{"not", "type", "oneOf", "uniqueItems", "const", "maxLength", "maxLength"}, keywords)
+	})
+}
+
+func TestObject_ValidateSchemaRefs(t *testing.T) {
+	logger := logrus.New()
+
+	t.Run("should validate the nested values against the recursive references", func(t *testing.T) {
+		schema, err := content.ParseSchema([]byte(`{"$defs": {"node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}}, "$ref": "#/$defs/node"}`))
+		require.NoError(t, err)
+
+		assert.NoError(t, content.Object(`{"children": [{"children": []}]}`).ValidateSchema(logger, schema))
+		assert.ErrorContains(t, content.Object(`{"children": [{"children": [1]}]}`).ValidateSchema(logger, schema), "/children/0/children/0")
+	})
+
+	t.Run("should fail for the circular references", func(t *testing.T) {
+		schema, err := content.ParseSchema([]byte(`{"anyOf": [{"$ref": "#"}]}`))
+		require.NoError(t, err)
+
+		err = content.Object(`{"name": "sample"}`).ValidateSchema(logger, schema)
+		assert.EqualError(t, err, "schema reference '#' is circular")
+
+		schema, err = content.ParseSchema([]byte(`{"$defs": {"a": {"$ref": "#/$defs/a"}}, "properties": {"name": {"$ref": "#/$defs/a"}}}`))
+		require.NoError(t, err)
+
+		err = content.Object(`{"name": "sample"}`).ValidateSchema(logger, schema)
+		assert.EqualError(t, err, "schema reference '#/$defs/a' is circular")
+	})
+}
+
+func TestParseSchema(t *testing.T) {
+	t.Run("should fail to parse the schema with invalid pattern", func(t *testing.T) {
+		_, err := content.ParseSchema([]byte(`{"properties": {"name": {"pattern": "[a-"}}}`))
+		assert.ErrorContains(t, err, "invalid pattern '[a-' in schema")
+	})
+
+	t.Run("should fail to parse the schema that is not an object", func(t *testing.T) {
+		_, err := content.ParseSchema([]byte(`[1, 2]`))
+		assert.EqualError(t, err, "schema should be an object or a boolean")
+	})
+
+	t.Run("should reject everything with the false schema", func(t *testing.T) {
+		schema, err := content.ParseSchema([]byte(`false`))
+		require.NoError(t, err)
+
+		err = content.Object(`{"name": "sample"}`).ValidateSchema(logrus.New(), schema)
+		assert.EqualError(t, err, "content does not conform to the schema: / (line 1, column 1): no value is allowed")
+	})
+}
@@ -0,0 +1,270 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml/parser"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/sirupsen/logrus"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// DefaultSniffSize is the number of bytes read from the start of the content to identify its type.
+const DefaultSniffSize = 64 * 1024
+
+// Sniffer identifies the type of content read from io.Reader, reading only the first SniffSize bytes
+// instead of the complete content, which suits scanning large files.
+// When the content is larger than SniffSize, the type is identified from the prefix alone, where the partial
+// value, element or line at the end of the prefix is ignored.
+type Sniffer struct {
+	// SniffSize is the number of bytes read to identify the type, defaults to DefaultSniffSize.
+	SniffSize int64 `json:"sniff_size,omitempty" yaml:"sniff_size,omitempty"`
+	// MaxSize is the size in bytes above which the content is rejected, zero disables the limit.
+	MaxSize int64 `json:"max_size,omitempty" yaml:"max_size,omitempty"`
+}
+
+// NewSniffer returns a new instance of Sniffer with the default sniff size and no size limit.
+func NewSniffer() *Sniffer {
+	return &Sniffer{SniffSize: DefaultSniffSize}
+}
+
+// Sniff identifies the type of the content read from the reader, the same way as CheckFileType.
+// It returns a reader with the complete content, including the bytes consumed to identify the type,
+// which fails once more than MaxSize bytes are read.
+func (sniffer *Sniffer) Sniff(log *logrus.Logger, reader io.Reader) (string, io.Reader, error) {
+	sniffSize := sniffer.SniffSize
+	if sniffSize <= 0 {
+		sniffSize = DefaultSniffSize
+	}
+
+	if sniffer.MaxSize > 0 {
+		sniffSize = min(sniffSize, sniffer.MaxSize)
+	}
+
+	prefix := make([]byte, sniffSize+1)
+
+	read, err := io.ReadFull(reader, prefix)
+	if err != nil && !stdErrors.Is(err, io.EOF) && !stdErrors.Is(err, io.ErrUnexpectedEOF) {
+		return FileTypeUnknown, nil, &errors.CommonError{Message: fmt.Sprintf("reading content errored with '%v'", err)}
+	}
+
+	prefix = prefix[:read]
+	complete := int64(read) <= sniffSize
+
+	if sniffer.MaxSize > 0 && int64(read) > sniffer.MaxSize {
+		return FileTypeUnknown, nil, sniffer.tooLarge()
+	}
+
+	if complete {
+		log.Debugf("identifying the input file type from the complete content of %d bytes", read)
+	} else {
+		log.Debugf("identifying the input file type from the first %d bytes of the content", sniffSize)
+	}
+
+	fileType := detectSniffed(log, string(prefix[:min(int64(read), sniffSize)]), complete)
+
+	log.Debugf("input file type identified as %s", fileType)
+
+	var rest io.Reader = bytes.NewReader(nil)
+	if !complete {
+		rest = reader
+	}
+
+	content := io.MultiReader(bytes.NewReader(prefix), rest)
+	if sniffer.MaxSize > 0 {
+		content = &limitedReader{reader: content, remaining: sniffer.MaxSize, sniffer: sniffer}
+	}
+
+	return fileType, content, nil
+}
+
+// SniffFile identifies the type of the file at the path, rejecting the files larger than MaxSize without reading them.
+func (sniffer *Sniffer) SniffFile(log *logrus.Logger, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return FileTypeUnknown, &errors.CommonError{Message: fmt.Sprintf("opening file '%s' errored with '%v'", path, err)}
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.Errorf("closing file '%s' errored with '%v'", path, closeErr)
+		}
+	}()
+
+	if info, statErr := file.Stat(); statErr == nil && sniffer.MaxSize > 0 && info.Size() > sniffer.MaxSize {
+		return FileTypeUnknown, sniffer.tooLarge()
+	}
+
+	fileType, _, err := sniffer.Sniff(log, file)
+
+	return fileType, err
+}
+
+func (sniffer *Sniffer) tooLarge() error {
+	return &errors.CommonError{Message: fmt.Sprintf("content exceeds the maximum size of %d bytes", sniffer.MaxSize)}
+}
+
+// limitedReader fails with an error once more than the remaining bytes are read, unlike io.LimitedReader which stops silently.
+// Every read following the failure returns the same error without reading further.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	sniffer   *Sniffer
+	err       error
+}
+
+func (reader *limitedReader) Read(out []byte) (int, error) {
+	if reader.err != nil {
+		return 0, reader.err
+	}
+
+	read, err := reader.reader.Read(out)
+
+	reader.remaining -= int64(read)
+	if reader.remaining < 0 {
+		reader.err = reader.sniffer.tooLarge()
+
+		return max(read+int(reader.remaining), 0), reader.err
+	}
+
+	return read, err
+}
+
+// detectSniffed identifies the type of the content in the order of CheckFileType, parsing it at most once per type.
+// The prefix of incomplete content is checked only for the start of a valid document.
+func detectSniffed(log *logrus.Logger, content string, complete bool) string {
+	content = normalizeContent(content)
+
+	switch {
+	case complete && json.Valid([]byte(content)):
+		return FileTypeJSON
+	case !complete && isJSONPrefix(content):
+		return FileTypeJSON
+	case complete && IsXML(content):
+		return FileTypeXML
+	case !complete && isXMLPrefix(content):
+		return FileTypeXML
+	}
+
+	if !complete {
+		content = completeLines(content)
+	}
+
+	if IsCSV(content) {
+		return FileTypeCSV
+	}
+
+	structured, scalar := sniffYAML(log, content)
+
+	switch {
+	case structured:
+		return FileTypeYAML
+	case IsTOML(content):
+		return FileTypeTOML
+	case scalar:
+		return FileTypeString
+	default:
+		return FileTypeUnknown
+	}
+}
+
+// isJSONPrefix checks if the content is the start of a single JSON object or array.
+func isJSONPrefix(content string) bool {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "{") && !strings.HasPrefix(content, "[") {
+		return false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(content))
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return stdErrors.Is(err, io.ErrUnexpectedEOF) || (stdErrors.Is(err, io.EOF) && depth > 0)
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return false
+		}
+	}
+}
+
+// isXMLPrefix checks if the content is the start of a document with a single root element, ignoring the partial tag at the end.
+func isXMLPrefix(content string) bool {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "<") {
+		return false
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(content[:strings.LastIndex(content, ">")+1]))
+	depth, roots := 0, 0
+
+	for {
+		// RawToken does not fail on the elements left open at the end of the prefix, unlike Token.
+		token, err := decoder.RawToken()
+		if err != nil {
+			return stdErrors.Is(err, io.EOF) && roots == 1
+		}
+
+		switch xmlToken := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(xmlToken)) != 0 {
+				return false
+			}
+		}
+	}
+}
+
+// sniffYAML parses the content once, reporting whether it is a YAML mapping or sequence, or a YAML scalar otherwise.
+func sniffYAML(log *logrus.Logger, content string) (structured bool, scalar bool) {
+	// github.com/goccy/go-yaml can produce panics
+	defer handlePanic(log)
+
+	file, err := parser.ParseBytes([]byte(content), 0, parser.AllowDuplicateMapKey())
+	if err == nil {
+		for _, doc := range file.Docs {
+			if doc != nil && isStructuredYAMLNode(doc.Body) {
+				return true, false
+			}
+		}
+	}
+
+	var node yamlv3.Node
+	if yamlv3.Unmarshal([]byte(content), &node) == nil && isStructuredYAMLV3Node(&node) {
+		return true, false
+	}
+
+	return false, err == nil
+}
+
+// completeLines drops the partial line at the end of the content.
+func completeLines(content string) string {
+	if index := strings.LastIndex(content, "\n"); index >= 0 {
+		return strings.TrimRight(content[:index], "\r")
+	}
+
+	return content
+}
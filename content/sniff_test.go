@@ -0,0 +1,108 @@
+package content_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniffer_Sniff(t *testing.T) {
+	logger := logrus.New()
+
+	t.Run("should identify the complete content the same way as CheckFileType", func(t *testing.T) {
+		for _, fixture := range []string{"sample.yaml", "sample.json", "sample.xml", "sample.csv"} {
+			data, err := os.ReadFile(filepath.Join("../fixtures", fixture))
+			require.NoError(t, err)
+
+			fileType, _, err := content.NewSniffer().Sniff(logger, strings.NewReader(string(data)))
+			require.NoError(t, err)
+			assert.Equal(t, content.Object(data).CheckFileType(logger), fileType, fixture)
+		}
+	})
+
+	t.Run("should identify the content larger than the sniff size from its prefix", func(t *testing.T) {
+		tests := map[string]string{
+			content.FileTypeJSON: `{"pipelines": [` + strings.Repeat(`{"name": "sample", "stages": ["build", "test"]},`, 100) + `{}]}`,
+			content.FileTypeXML:  "<pipelines>" + strings.Repeat("<pipeline><name>sample</name></pipeline>", 100) + "</pipelines>",
+			content.FileTypeCSV:  "name,stage\n" + strings.Repeat("sample,build\n", 100),
+			content.FileTypeYAML: "pipelines:\n" + strings.Repeat("  - name: sample\n    stages: [build, test]\n", 100),
+			content.FileTypeTOML: "name = \"sample\"\n" + tomlStages(100),
+		}
+
+		for expected, data := range tests {
+			sniffer := &content.Sniffer{SniffSize: 100}
+
+			fileType, reader, err := sniffer.Sniff(logger, strings.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, expected, fileType)
+
+			out, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, data, string(out))
+		}
+	})
+
+	t.Run("should fail when the content exceeds the maximum size", func(t *testing.T) {
+		sniffer := &content.Sniffer{MaxSize: 10}
+
+		_, _, err := sniffer.Sniff(logger, strings.NewReader("name: sample\n"))
+		assert.EqualError(t, err, "content exceeds the maximum size of 10 bytes")
+	})
+
+	t.Run("should fail reading the content past the maximum size", func(t *testing.T) {
+		sniffer := &content.Sniffer{SniffSize: 20, MaxSize: 30}
+
+		fileType, reader, err := sniffer.Sniff(logger, strings.NewReader(strings.Repeat("name: sample\n", 5)))
+		require.NoError(t, err)
+		assert.Equal(t, content.FileTypeYAML, fileType)
+
+		out, err := io.ReadAll(reader)
+		assert.EqualError(t, err, "content exceeds the maximum size of 30 bytes")
+		assert.Len(t, out, 30)
+
+		for range 2 {
+			read, err := reader.Read(make([]byte, 8))
+			assert.EqualError(t, err, "content exceeds the maximum size of 30 bytes")
+			assert.Zero(t, read)
+		}
+	})
+}
+
+func tomlStages(count int) string {
+	var stages strings.Builder
+	for index := range count {
+		stages.WriteString(fmt.Sprintf("stage_%d = \"build\"\n", index))
+	}
+
+	return stages.String()
+}
+
+func TestSniffer_SniffFile(t *testing.T) {
+	logger := logrus.New()
+
+	t.Run("should identify the type of the file", func(t *testing.T) {
+		fileType, err := content.NewSniffer().SniffFile(logger, "../fixtures/sample.json")
+		require.NoError(t, err)
+		assert.Equal(t, content.FileTypeJSON, fileType)
+	})
+
+	t.Run("should reject the file larger than the maximum size", func(t *testing.T) {
+		sniffer := &content.Sniffer{MaxSize: 5}
+
+		_, err := sniffer.SniffFile(logger, "../fixtures/sample.json")
+		assert.EqualError(t, err, "content exceeds the maximum size of 5 bytes")
+	})
+
+	t.Run("should fail when the file does not exist", func(t *testing.T) {
+		_, err := content.NewSniffer().SniffFile(logger, "../fixtures/missing.json")
+		assert.ErrorContains(t, err, "opening file '../fixtures/missing.json' errored with")
+	})
+}
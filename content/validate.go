@@ -0,0 +1,183 @@
+package content
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/sirupsen/logrus"
+)
+
+// snippetContext is the number of lines printed before the offending line in the snippets of ParseError.
+const snippetContext = 2
+
+// jsonUnexpectedEnd is the message of the syntax errors for the JSON content ending early.
+const jsonUnexpectedEnd = "unexpected end of JSON input"
+
+// ParseError describes why the content could not be parsed, with the position of the offending token in the source.
+// Line and Column start at 1.
+type ParseError struct {
+	Format  string `json:"format,omitempty" yaml:"format,omitempty"`
+	Line    int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Column  int    `json:"column,omitempty" yaml:"column,omitempty"`
+	Token   string `json:"token,omitempty" yaml:"token,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	source  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid %s at line %d, column %d: %s", e.Format, e.Line, e.Column, e.Message)
+}
+
+// Snippet returns the lines of the source leading to the offending line, with a caret pointing at the offending column.
+func (e *ParseError) Snippet(colored bool) string {
+	lines := strings.Split(e.source, "\n")
+	if e.Line < 1 || e.Line > len(lines) {
+		return ""
+	}
+
+	gutterColor, caretColor := color.New(color.Faint), color.New(color.FgRed, color.Bold)
+	if colored {
+		gutterColor.EnableColor()
+		caretColor.EnableColor()
+	} else {
+		gutterColor.DisableColor()
+		caretColor.DisableColor()
+	}
+
+	width := len(fmt.Sprint(e.Line))
+
+	var snippet strings.Builder
+
+	for number := max(e.Line-snippetContext, 1); number <= e.Line; number++ {
+		snippet.WriteString(gutterColor.Sprintf("%*d | ", width, number) + strings.TrimRight(lines[number-1], "\r") + "\n")
+	}
+
+	snippet.WriteString(gutterColor.Sprintf("%*s | ", width, "") + strings.Repeat(" ", max(e.Column-1, 0)) + caretColor.Sprint("^"))
+
+	return snippet.String()
+}
+
+// FormatError returns the error along with the snippet of the source, colored when colored is set.
+func (e *ParseError) FormatError(colored bool) string {
+	message := color.New(color.FgRed)
+	if colored {
+		message.EnableColor()
+	} else {
+		message.DisableColor()
+	}
+
+	return message.Sprint(e.Error()) + "\n" + e.Snippet(colored)
+}
+
+// ValidateJSON parses the content as JSON, returning *ParseError when it is malformed.
+func ValidateJSON(content string) error {
+	var js any
+
+	err := json.Unmarshal([]byte(content), &js)
+	if err == nil {
+		return nil
+	}
+
+	// the content ended early unless the offending character is identified, pointing past the last character.
+	offset, token := len(strings.TrimRight(content, " \t\r\n")), ""
+
+	var syntaxError *json.SyntaxError
+	if stdErrors.As(err, &syntaxError) && syntaxError.Error() != jsonUnexpectedEnd && int(syntaxError.Offset) <= len(content) {
+		offset = min(max(int(syntaxError.Offset)-1, 0), len(content)-1)
+		token = tokenAt(content, offset)
+	}
+
+	line, column := position(content, offset)
+
+	return &ParseError{
+		Format:  FileTypeJSON,
+		Line:    line,
+		Column:  column,
+		Token:   token,
+		Message: strings.TrimPrefix(err.Error(), "json: "),
+		source:  content,
+	}
+}
+
+// ValidateYAML parses the content as YAML, returning *ParseError when it is malformed.
+func ValidateYAML(log *logrus.Logger, content string) (err error) {
+	parseError := &ParseError{Format: FileTypeYAML, Line: 1, Column: 1, Message: "parsing the content failed", source: content}
+
+	// github.com/goccy/go-yaml can produce panics, the content is reported as malformed when it does.
+	err = parseError
+	defer handlePanic(log)
+
+	_, err = parser.ParseBytes([]byte(content), 0)
+	if err == nil {
+		return nil
+	}
+
+	parseError.Message = err.Error()
+
+	var yamlError yaml.Error
+	if stdErrors.As(err, &yamlError) {
+		parseError.Message = yamlError.GetMessage()
+
+		if token := yamlError.GetToken(); token != nil && token.Position != nil {
+			parseError.Line, parseError.Column = token.Position.Line, token.Position.Column
+			parseError.Token = strings.TrimSpace(token.Value)
+		}
+	}
+
+	return parseError
+}
+
+// ValidateCSV parses the content as CSV, returning *ParseError when it is malformed or the rows have different number of fields.
+func ValidateCSV(content string) error {
+	_, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err == nil {
+		return nil
+	}
+
+	parseError := &ParseError{Format: FileTypeCSV, Line: 1, Column: 1, Message: err.Error(), source: content}
+
+	var csvError *csv.ParseError
+	if stdErrors.As(err, &csvError) {
+		parseError.Line, parseError.Column = csvError.Line, max(csvError.Column, 1)
+		parseError.Message = csvError.Err.Error()
+
+		// the column of csv.ParseError is the byte index in the line, it is reported as the index of the character.
+		lines := strings.Split(content, "\n")
+		if csvError.Line <= len(lines) {
+			line := lines[csvError.Line-1]
+			offset := min(max(csvError.Column-1, 0), len(line))
+			parseError.Column = utf8.RuneCountInString(line[:offset]) + 1
+			parseError.Token = tokenAt(line, offset)
+		}
+	}
+
+	return parseError
+}
+
+// position returns the line and column of the byte offset in the content.
+func position(content string, offset int) (int, int) {
+	prefix := []byte(content[:min(offset, len(content))])
+	line := bytes.Count(prefix, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(prefix, '\n') + 1
+
+	return line, utf8.RuneCount(prefix[lineStart:]) + 1
+}
+
+// tokenAt returns the character at the byte offset of the content.
+func tokenAt(content string, offset int) string {
+	if offset >= len(content) {
+		return ""
+	}
+
+	character, _ := utf8.DecodeRuneInString(content[offset:])
+
+	return string(character)
+}
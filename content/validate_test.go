@@ -0,0 +1,111 @@
+package content_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateYAML(t *testing.T) {
+	t.Run("should validate yaml successfully", func(t *testing.T) {
+		data, err := os.ReadFile("../fixtures/sample.yaml")
+		require.NoError(t, err)
+
+		assert.NoError(t, content.ValidateYAML(logrus.New(), string(data)))
+	})
+
+	t.Run("should report the position of the faulty yaml", func(t *testing.T) {
+		data, err := os.ReadFile("../fixtures/sample_faulty.yaml")
+		require.NoError(t, err)
+
+		err = content.ValidateYAML(logrus.New(), string(data))
+
+		var parseError *content.ParseError
+		require.ErrorAs(t, err, &parseError)
+		assert.Equal(t, 12, parseError.Line)
+		assert.Equal(t, 1, parseError.Column)
+		assert.Equal(t, "xmas-fifth-day", parseError.Token)
+		assert.EqualError(t, err, "invalid yaml at line 12, column 1: non-map value is specified")
+		assert.Equal(t, "10 |   - louie\n11 |   - fred\n12 | xmas-fifth-day\n   | ^", parseError.Snippet(false))
+	})
+}
+
+func TestValidateJSON(t *testing.T) {
+	t.Run("should validate json successfully", func(t *testing.T) {
+		assert.NoError(t, content.ValidateJSON(`{"name": "sample"}`))
+	})
+
+	t.Run("should report the offending character", func(t *testing.T) {
+		err := content.ValidateJSON("{\n  \"name\": \"sample\",\n  \"enabled\": yes\n}")
+
+		var parseError *content.ParseError
+		require.ErrorAs(t, err, &parseError)
+		assert.Equal(t, 3, parseError.Line)
+		assert.Equal(t, 14, parseError.Column)
+		assert.Equal(t, "y", parseError.Token)
+		assert.Equal(t, "1 | {\n2 |   \"name\": \"sample\",\n3 |   \"enabled\": yes\n  |              ^", parseError.Snippet(false))
+	})
+
+	t.Run("should point past the content that ended early", func(t *testing.T) {
+		err := content.ValidateJSON("{\n  \"name\": \"sample\"\n")
+
+		var parseError *content.ParseError
+		require.ErrorAs(t, err, &parseError)
+		assert.EqualError(t, err, "invalid json at line 2, column 19: unexpected end of JSON input")
+		assert.Empty(t, parseError.Token)
+	})
+
+	t.Run("should report the offending last character", func(t *testing.T) {
+		err := content.ValidateJSON(`{"a": 1,}`)
+
+		var parseError *content.ParseError
+		require.ErrorAs(t, err, &parseError)
+		assert.Equal(t, 9, parseError.Column)
+		assert.Equal(t, "}", parseError.Token)
+		assert.Equal(t, "1 | {\"a\": 1,}\n  |         ^", parseError.Snippet(false))
+	})
+}
+
+func TestValidateCSV(t *testing.T) {
+	t.Run("should validate csv successfully", func(t *testing.T) {
+		assert.NoError(t, content.ValidateCSV("name,status\nbuild,Passed\n"))
+	})
+
+	t.Run("should report rows with different number of fields", func(t *testing.T) {
+		err := content.ValidateCSV("name,status\nbuild,Passed,10\n")
+		require.EqualError(t, err, "invalid csv at line 2, column 1: wrong number of fields")
+	})
+
+	t.Run("should report the column of the offending quote by characters", func(t *testing.T) {
+		err := content.ValidateCSV("a,b\nééé,x\"y")
+
+		var parseError *content.ParseError
+		require.ErrorAs(t, err, &parseError)
+		assert.Equal(t, 6, parseError.Column)
+		assert.Equal(t, `"`, parseError.Token)
+		assert.Equal(t, "1 | a,b\n2 | ééé,x\"y\n  |      ^", parseError.Snippet(false))
+	})
+
+	t.Run("should report the offending quote", func(t *testing.T) {
+		err := content.ValidateCSV("name,status\nbuild,Pass\"ed\n")
+
+		var parseError *content.ParseError
+		require.ErrorAs(t, err, &parseError)
+		assert.Equal(t, 11, parseError.Column)
+		assert.Equal(t, `"`, parseError.Token)
+		assert.Equal(t, "1 | name,status\n2 | build,Pass\"ed\n  |           ^", parseError.Snippet(false))
+	})
+
+	t.Run("should color the error when enabled", func(t *testing.T) {
+		err := content.ValidateCSV("name,status\nbuild,Pass\"ed\n")
+
+		var parseError *content.ParseError
+		require.ErrorAs(t, err, &parseError)
+		assert.Contains(t, parseError.FormatError(true), "\x1b[31m")
+		assert.NotContains(t, parseError.FormatError(false), "\x1b[")
+	})
+}
@@ -0,0 +1,149 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	stdErrors "errors"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	xmlRootElement = "root"
+	xmlItemElement = "item"
+)
+
+// IsXML checks if the passed content is XML, a well-formed document with a single root element.
+func IsXML(content string) bool {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "<") {
+		return false
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	depth, roots := 0, 0
+
+	for {
+		token, err := decoder.Token()
+		if stdErrors.Is(err, io.EOF) {
+			return roots == 1 && depth == 0
+		}
+
+		if err != nil {
+			return false
+		}
+
+		switch xmlToken := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(xmlToken)) != 0 {
+				return false
+			}
+		}
+	}
+}
+
+// MarshalXML converts data into indented XML.
+// Values that encoding/xml cannot handle, such as maps, are converted with their keys as elements under a 'root' element
+// and list elements as 'item' elements.
+func MarshalXML(data any, indent string) ([]byte, error) {
+	out, err := xml.MarshalIndent(data, "", indent)
+
+	var unsupportedTypeError *xml.UnsupportedTypeError
+	if !stdErrors.As(err, &unsupportedTypeError) {
+		return out, err
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// numbers are decoded as json.Number, retaining their text instead of printing large integers in exponent form.
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+
+	var generic any
+	if err = decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return xml.MarshalIndent(xmlNode{name: xmlRootElement, value: generic}, "", indent)
+}
+
+// xmlNode encodes the generic values decoded from JSON as XML elements.
+type xmlNode struct {
+	name  string
+	value any
+}
+
+// MarshalXML implements xml.Marshaler.
+func (node xmlNode) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = node.name
+
+	switch value := node.value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if err := encoder.Encode(xmlNode{name: xmlElementName(key), value: value[key]}); err != nil {
+				return err
+			}
+		}
+
+		return encoder.EncodeToken(start.End())
+	case []any:
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+
+		for _, item := range value {
+			if err := encoder.Encode(xmlNode{name: xmlItemElement, value: item}); err != nil {
+				return err
+			}
+		}
+
+		return encoder.EncodeToken(start.End())
+	case nil:
+		return encoder.EncodeElement("", start)
+	default:
+		return encoder.EncodeElement(value, start)
+	}
+}
+
+// xmlElementName makes the key passed a valid XML element name.
+func xmlElementName(key string) string {
+	name := strings.Map(func(char rune) rune {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) || char == '_' || char == '-' || char == '.' {
+			return char
+		}
+
+		return '_'
+	}, key)
+
+	if first, _ := utf8.DecodeRuneInString(name); !unicode.IsLetter(first) && first != '_' {
+		name = "_" + name
+	}
+
+	return name
+}
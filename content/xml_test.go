@@ -0,0 +1,72 @@
+package content_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsXML(t *testing.T) {
+	t.Run("should validate content as xml", func(t *testing.T) {
+		fileData, err := os.ReadFile("../fixtures/sample.xml")
+		require.NoError(t, err)
+
+		assert.True(t, content.IsXML(string(fileData)))
+	})
+
+	t.Run("should fail for malformed xml", func(t *testing.T) {
+		fileData, err := os.ReadFile("../fixtures/sample_faulty.xml")
+		require.NoError(t, err)
+
+		assert.False(t, content.IsXML(string(fileData)))
+	})
+
+	t.Run("should fail for xml with multiple root elements", func(t *testing.T) {
+		assert.False(t, content.IsXML("<a>1</a><b>2</b>"))
+	})
+
+	t.Run("should fail for content that is not xml", func(t *testing.T) {
+		assert.False(t, content.IsXML("name: testing"))
+		assert.False(t, content.IsXML("<a>1</a> trailing"))
+	})
+}
+
+func TestMarshalXML(t *testing.T) {
+	t.Run("should marshal structs with encoding/xml", func(t *testing.T) {
+		type server struct {
+			Name string `xml:"name,attr"`
+			Port int    `xml:"port"`
+		}
+
+		out, err := content.MarshalXML(server{Name: "gocd", Port: 8153}, "  ")
+		require.NoError(t, err)
+		assert.Equal(t, "<server name=\"gocd\">\n  <port>8153</port>\n</server>", string(out))
+	})
+
+	t.Run("should marshal maps with keys as elements", func(t *testing.T) {
+		out, err := content.MarshalXML(map[string]any{
+			"name":      "gocd",
+			"1st-agent": nil,
+			"agents":    []string{"agent-1", "agent-2"},
+		}, "  ")
+		require.NoError(t, err)
+		assert.Equal(t, `<root>
+  <_1st-agent></_1st-agent>
+  <agents>
+    <item>agent-1</item>
+    <item>agent-2</item>
+  </agents>
+  <name>gocd</name>
+</root>`, string(out))
+		assert.True(t, content.IsXML(string(out)))
+	})
+
+	t.Run("should marshal the numbers of maps without exponent", func(t *testing.T) {
+		out, err := content.MarshalXML(map[string]any{"count": 1000000, "size": int64(12345678901), "ratio": 0.25}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "<root><count>1000000</count><ratio>0.25</ratio><size>12345678901</size></root>", string(out))
+	})
+}
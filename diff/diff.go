@@ -2,12 +2,11 @@
 package diff
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/fatih/color"
-	"github.com/goccy/go-yaml"
+	"github.com/nikhilsbhat/common/content"
 	"github.com/nikhilsbhat/common/errors"
 	"github.com/pmezard/go-difflib/difflib"
 	"github.com/sirupsen/logrus"
@@ -22,7 +21,9 @@ type Config struct {
 	NoColor      bool   `json:"no_color,omitempty" yaml:"no_color,omitempty"`
 	Format       string `json:"format,omitempty" yaml:"format,omitempty"`
 	ContextLines int    `json:"context_lines,omitempty" yaml:"context_lines,omitempty"`
-	log          *logrus.Logger
+	// Encoding customises the output of String.
+	Encoding content.EncodeOptions `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	log      *logrus.Logger
 }
 
 // NewDiff returns a new instance of Config.
@@ -34,13 +35,15 @@ func NewDiff(format string, noColor bool, log *logrus.Logger) *Config {
 	}
 }
 
-// Diff identifies the discrepancies between two provided objects, which can be in formats such as YAML or JSON.
+// Diff identifies the discrepancies between two provided objects, which can be in formats such as YAML, JSON or XML.
 func (cfg *Config) Diff(oldData, newData string) (bool, string, error) {
 	switch cfg.Format {
 	case "yaml":
 		cfg.log.Debug("loading diff in yaml format")
 	case "json":
 		cfg.log.Debug("loading diff in json format")
+	case "xml":
+		cfg.log.Debug("loading diff in xml format")
 	default:
 		return false, "", &errors.CommonError{Message: fmt.Sprintf("unknown format, cannot calculate diff for the format '%s'", cfg.Format)}
 	}
@@ -57,36 +60,29 @@ func (cfg *Config) Diff(oldData, newData string) (bool, string, error) {
 	return true, strings.Join(diffIdentified, "\n"), nil
 }
 
-// String returns the string representation of the DataStructure in the specified format.
+// String returns the string representation of the DataStructure in the specified format, encoded with Config.Encoding.
 func (cfg *Config) String(input any) (string, error) {
+	var (
+		out []byte
+		err error
+	)
+
 	switch strings.ToLower(cfg.Format) {
 	case "yaml":
-		yamlIndent := 2
-
-		encodeOptions := []yaml.EncodeOption{
-			yaml.Indent(yamlIndent),
-			yaml.IndentSequence(true),
-			yaml.UseLiteralStyleIfMultiline(true),
-		}
-
-		out, err := yaml.MarshalWithOptions(input, encodeOptions...)
-		if err != nil {
-			return "", err
-		}
-
-		yamlString := strings.Join([]string{"---", string(out)}, "\n")
-
-		return yamlString, nil
+		out, err = cfg.Encoding.EncodeYAML(input)
 	case "json":
-		out, err := json.MarshalIndent(input, "", "     ")
-		if err != nil {
-			return "", err
-		}
-
-		return string(out), nil
+		out, err = cfg.Encoding.EncodeJSON(input)
+	case "xml":
+		out, err = cfg.Encoding.EncodeXML(input)
 	default:
 		return "", &errors.CommonError{Message: fmt.Sprintf("type '%s' is not supported for loading diff", cfg.Format)}
 	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
 }
 
 func (cfg *Config) diff(content1, content2 string) ([]string, error) {
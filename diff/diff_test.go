@@ -3,6 +3,7 @@ package diff_test
 import (
 	"testing"
 
+	"github.com/nikhilsbhat/common/content"
 	"github.com/nikhilsbhat/common/diff"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -29,6 +30,34 @@ func TestConfig_String(t *testing.T) {
 		assert.JSONEq(t, `{"name":"testing"}`, actual)
 	})
 
+	t.Run("renders with encoding options", func(t *testing.T) {
+		cfg := diff.NewDiff("json", true, logrus.New())
+		cfg.Encoding = content.EncodeOptions{Indent: 2}
+
+		actual, err := cfg.String(map[string]string{"name": "testing"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "{\n  \"name\": \"testing\"\n}", actual)
+	})
+
+	t.Run("renders xml", func(t *testing.T) {
+		cfg := diff.NewDiff("xml", true, logrus.New())
+
+		actual, err := cfg.String(map[string]string{"name": "testing"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "<root>\n  <name>testing</name>\n</root>", actual)
+	})
+
+	t.Run("renders xml numbers without exponent", func(t *testing.T) {
+		cfg := diff.NewDiff("xml", true, logrus.New())
+
+		actual, err := cfg.String(map[string]int64{"size": 12345678901})
+
+		require.NoError(t, err)
+		assert.Equal(t, "<root>\n  <size>12345678901</size>\n</root>", actual)
+	})
+
 	t.Run("returns error for unsupported format", func(t *testing.T) {
 		cfg := diff.NewDiff("toml", true, logrus.New())
 
@@ -62,6 +91,17 @@ func TestConfig_Diff(t *testing.T) {
 		assert.Contains(t, actual, "+name: new")
 	})
 
+	t.Run("returns diff for changed xml content", func(t *testing.T) {
+		cfg := diff.NewDiff("xml", true, logrus.New())
+
+		found, actual, err := cfg.Diff("<server>\n  <port>8153</port>\n</server>\n", "<server>\n  <port>8154</port>\n</server>\n")
+
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Contains(t, actual, "-  <port>8153</port>")
+		assert.Contains(t, actual, "+  <port>8154</port>")
+	})
+
 	t.Run("colors diff when enabled", func(t *testing.T) {
 		cfg := diff.NewDiff("json", false, logrus.New())
 
@@ -0,0 +1,42 @@
+// Package terminal identifies the writers that are terminals, shared by the packages rendering for them.
+package terminal
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// IsTerminal reports whether the writer is a file attached to a terminal.
+func IsTerminal(writer io.Writer) bool {
+	_, ok := fileDescriptor(writer)
+
+	return ok
+}
+
+// Height returns the height of the terminal the writer is attached to, false when the writer is not a terminal.
+func Height(writer io.Writer) (int, bool, error) {
+	fd, ok := fileDescriptor(writer)
+	if !ok {
+		return 0, false, nil
+	}
+
+	_, height, err := term.GetSize(fd)
+	if err != nil {
+		return 0, true, err
+	}
+
+	return height, true, nil
+}
+
+func fileDescriptor(writer io.Writer) (int, bool) {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return 0, false
+	}
+
+	fd := int(file.Fd())
+
+	return fd, term.IsTerminal(fd)
+}
@@ -0,0 +1,29 @@
+package terminal_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nikhilsbhat/common/internal/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTerminal(t *testing.T) {
+	t.Run("should not identify buffers and regular files as terminals", func(t *testing.T) {
+		file, err := os.Create(filepath.Join(t.TempDir(), "output.txt"))
+		require.NoError(t, err)
+
+		defer file.Close()
+
+		assert.False(t, terminal.IsTerminal(new(bytes.Buffer)))
+		assert.False(t, terminal.IsTerminal(file))
+
+		height, ok, err := terminal.Height(file)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Zero(t, height)
+	})
+}
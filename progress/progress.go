@@ -0,0 +1,339 @@
+// Package progress provides progress bars and spinners for long-running operations in CLIs.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nikhilsbhat/common/internal/terminal"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultInterval is the interval at which the bars and spinners are redrawn once the Manager is started.
+	DefaultInterval = 100 * time.Millisecond
+	// DefaultBarWidth is the number of characters used to draw the bars.
+	DefaultBarWidth = 30
+
+	cursorUp       = "\x1b[%dA"
+	clearLine      = "\x1b[2K"
+	clearToEnd     = "\x1b[J"
+	percent        = 100
+	spinnerDone    = "✓"
+	spinnerFailed  = "✗"
+	barFilled      = "="
+	barHead        = ">"
+	barEmpty       = " "
+	unknownPercent = "  ?%"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// indicator is implemented by Bar and Spinner, returning the line drawn for them.
+type indicator interface {
+	line(frame int) string
+}
+
+// Manager draws multiple bars and spinners on the writer, redrawing them in place.
+// It disables itself when the writer is not a terminal, in which case the bars and spinners are tracked but never drawn.
+// The methods of Manager, Bar and Spinner are safe for concurrent use.
+type Manager struct {
+	Interval   time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	BarWidth   int           `json:"bar_width,omitempty" yaml:"bar_width,omitempty"`
+	writer     io.Writer
+	logger     *logrus.Logger
+	enabled    bool
+	mutex      sync.Mutex
+	indicators []indicator
+	drawn      int
+	frame      int
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewManager returns a new instance of Manager that draws on the writer, enabled only when the writer is a terminal.
+func NewManager(writer io.Writer, logger *logrus.Logger) *Manager {
+	if writer == nil {
+		writer = os.Stderr
+	}
+
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	enabled := terminal.IsTerminal(writer)
+	if !enabled {
+		logger.Debug("disabling progress since the writer is not a terminal")
+	}
+
+	return &Manager{
+		Interval: DefaultInterval,
+		BarWidth: DefaultBarWidth,
+		writer:   writer,
+		logger:   logger,
+		enabled:  enabled,
+	}
+}
+
+// WithEnabled overrides the terminal detection, to force or disable the progress.
+func (manager *Manager) WithEnabled(enabled bool) *Manager {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.enabled = enabled
+
+	return manager
+}
+
+// Enabled reports whether the bars and spinners are drawn.
+func (manager *Manager) Enabled() bool {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	return manager.enabled
+}
+
+// AddBar adds a new bar tracking the progress up to total, the bar shows the current count without percentage when total is not positive.
+func (manager *Manager) AddBar(name string, total int64) *Bar {
+	bar := &Bar{manager: manager, name: name, total: total}
+	manager.add(bar)
+
+	return bar
+}
+
+// AddSpinner adds a new spinner with the message.
+func (manager *Manager) AddSpinner(message string) *Spinner {
+	spinner := &Spinner{manager: manager, message: message}
+	manager.add(spinner)
+
+	return spinner
+}
+
+func (manager *Manager) add(indicator indicator) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	manager.indicators = append(manager.indicators, indicator)
+}
+
+// Start redraws the bars and spinners every Manager.Interval until Stop is called.
+func (manager *Manager) Start() {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if !manager.enabled || manager.stop != nil {
+		return
+	}
+
+	interval := manager.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	manager.stop, manager.done = make(chan struct{}), make(chan struct{})
+
+	go manager.run(interval, manager.stop, manager.done)
+}
+
+func (manager *Manager) run(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := manager.Render(); err != nil {
+				manager.logger.Debugf("drawing progress errored with '%v'", err)
+			}
+		}
+	}
+}
+
+// Stop stops redrawing and clears the bars and spinners, so that the output that follows is printed cleanly.
+// It is safe to call Stop multiple times.
+func (manager *Manager) Stop() error {
+	manager.mutex.Lock()
+	stop, done := manager.stop, manager.done
+	manager.stop, manager.done = nil, nil
+	manager.mutex.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	return manager.Clear()
+}
+
+// Render draws the bars and spinners once, replacing the lines drawn earlier.
+func (manager *Manager) Render() error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if !manager.enabled {
+		return nil
+	}
+
+	var out strings.Builder
+
+	manager.clear(&out)
+
+	for _, indicator := range manager.indicators {
+		out.WriteString(clearLine + indicator.line(manager.frame) + "\n")
+	}
+
+	manager.frame++
+	manager.drawn = len(manager.indicators)
+
+	_, err := io.WriteString(manager.writer, out.String())
+
+	return err
+}
+
+// Clear erases the bars and spinners drawn, they are drawn again on the next Render.
+func (manager *Manager) Clear() error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if !manager.enabled || manager.drawn == 0 {
+		return nil
+	}
+
+	var out strings.Builder
+
+	manager.clear(&out)
+	manager.drawn = 0
+
+	_, err := io.WriteString(manager.writer, out.String())
+
+	return err
+}
+
+func (manager *Manager) clear(out *strings.Builder) {
+	if manager.drawn == 0 {
+		return
+	}
+
+	out.WriteString(fmt.Sprintf(cursorUp, manager.drawn) + clearToEnd)
+}
+
+// Bar tracks the progress of an operation with known size.
+type Bar struct {
+	manager *Manager
+	name    string
+	total   int64
+	current int64
+}
+
+// Increment adds count to the progress of the bar.
+func (bar *Bar) Increment(count int64) {
+	bar.manager.mutex.Lock()
+	defer bar.manager.mutex.Unlock()
+
+	bar.current += count
+}
+
+// SetCurrent sets the progress of the bar.
+func (bar *Bar) SetCurrent(current int64) {
+	bar.manager.mutex.Lock()
+	defer bar.manager.mutex.Unlock()
+
+	bar.current = current
+}
+
+// Current returns the progress of the bar.
+func (bar *Bar) Current() int64 {
+	bar.manager.mutex.Lock()
+	defer bar.manager.mutex.Unlock()
+
+	return bar.current
+}
+
+// Done completes the bar, filling it up to the total.
+func (bar *Bar) Done() {
+	bar.manager.mutex.Lock()
+	defer bar.manager.mutex.Unlock()
+
+	if bar.total > 0 {
+		bar.current = bar.total
+	}
+}
+
+func (bar *Bar) line(_ int) string {
+	width := bar.manager.BarWidth
+	if width <= 0 {
+		width = DefaultBarWidth
+	}
+
+	if bar.total <= 0 {
+		return fmt.Sprintf("%s [%s] %s (%d)", bar.name, strings.Repeat(barEmpty, width), unknownPercent, bar.current)
+	}
+
+	current := min(max(bar.current, 0), bar.total)
+	filled := int(current * int64(width) / bar.total)
+
+	var drawn string
+
+	switch {
+	case filled >= width:
+		drawn = strings.Repeat(barFilled, width)
+	case filled == 0:
+		drawn = strings.Repeat(barEmpty, width)
+	default:
+		drawn = strings.Repeat(barFilled, filled-1) + barHead + strings.Repeat(barEmpty, width-filled)
+	}
+
+	return fmt.Sprintf("%s [%s] %3d%% (%d/%d)", bar.name, drawn, current*percent/bar.total, current, bar.total)
+}
+
+// Spinner indicates an operation of unknown size is in progress.
+type Spinner struct {
+	manager *Manager
+	message string
+	status  string
+}
+
+// SetMessage updates the message of the spinner.
+func (spinner *Spinner) SetMessage(message string) {
+	spinner.manager.mutex.Lock()
+	defer spinner.manager.mutex.Unlock()
+
+	spinner.message = message
+}
+
+// Done stops the spinner marking it as succeeded, with the message passed when not empty.
+func (spinner *Spinner) Done(message string) {
+	spinner.finish(spinnerDone, message)
+}
+
+// Fail stops the spinner marking it as failed, with the message passed when not empty.
+func (spinner *Spinner) Fail(message string) {
+	spinner.finish(spinnerFailed, message)
+}
+
+func (spinner *Spinner) finish(status, message string) {
+	spinner.manager.mutex.Lock()
+	defer spinner.manager.mutex.Unlock()
+
+	spinner.status = status
+	if len(message) != 0 {
+		spinner.message = message
+	}
+}
+
+func (spinner *Spinner) line(frame int) string {
+	status := spinner.status
+	if len(status) == 0 {
+		status = spinnerFrames[frame%len(spinnerFrames)]
+	}
+
+	return status + " " + spinner.message
+}
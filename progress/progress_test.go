@@ -0,0 +1,104 @@
+package progress_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/common/progress"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Render(t *testing.T) {
+	t.Run("should draw bars and spinners and redraw them in place", func(t *testing.T) {
+		out := new(bytes.Buffer)
+
+		manager := progress.NewManager(out, logrus.New()).WithEnabled(true)
+		manager.BarWidth = 10
+
+		bar := manager.AddBar("export", 4)
+		spinner := manager.AddSpinner("waiting for pipeline")
+
+		bar.Increment(1)
+		require.NoError(t, manager.Render())
+		assert.Equal(t, "\x1b[2Kexport [=>        ]  25% (1/4)\n\x1b[2K⠋ waiting for pipeline\n", out.String())
+
+		out.Reset()
+		bar.Done()
+		spinner.Done("pipeline passed")
+		require.NoError(t, manager.Render())
+		assert.Equal(t, "\x1b[2A\x1b[J\x1b[2Kexport [==========] 100% (4/4)\n\x1b[2K✓ pipeline passed\n", out.String())
+
+		out.Reset()
+		require.NoError(t, manager.Stop())
+		assert.Equal(t, "\x1b[2A\x1b[J", out.String())
+
+		out.Reset()
+		require.NoError(t, manager.Stop())
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("should draw bars with unknown total", func(t *testing.T) {
+		out := new(bytes.Buffer)
+
+		manager := progress.NewManager(out, logrus.New()).WithEnabled(true)
+		manager.BarWidth = 4
+
+		bar := manager.AddBar("objects", 0)
+		bar.SetCurrent(7)
+
+		require.NoError(t, manager.Render())
+		assert.Equal(t, "\x1b[2Kobjects [    ]   ?% (7)\n", out.String())
+	})
+
+	t.Run("should not draw when the writer is not a terminal", func(t *testing.T) {
+		out := new(bytes.Buffer)
+
+		manager := progress.NewManager(out, logrus.New())
+		assert.False(t, manager.Enabled())
+
+		bar := manager.AddBar("export", 10)
+		bar.Increment(3)
+		manager.Start()
+
+		require.NoError(t, manager.Render())
+		require.NoError(t, manager.Stop())
+		assert.Empty(t, out.String())
+		assert.Equal(t, int64(3), bar.Current())
+	})
+
+	t.Run("should track concurrent bars while redrawing", func(t *testing.T) {
+		out := new(bytes.Buffer)
+
+		manager := progress.NewManager(out, logrus.New()).WithEnabled(true)
+		manager.Interval = time.Millisecond
+
+		bars := []*progress.Bar{manager.AddBar("first", 100), manager.AddBar("second", 100)}
+
+		manager.Start()
+
+		var waitGroup sync.WaitGroup
+
+		for _, bar := range bars {
+			waitGroup.Add(1)
+
+			go func() {
+				defer waitGroup.Done()
+
+				for range 100 {
+					bar.Increment(1)
+				}
+			}()
+		}
+
+		waitGroup.Wait()
+		require.NoError(t, manager.Stop())
+
+		for _, bar := range bars {
+			assert.Equal(t, int64(100), bar.Current())
+		}
+	})
+}
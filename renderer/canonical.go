@@ -0,0 +1,110 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// maxExactFloat is the largest integer that float64 holds without losing precision.
+const maxExactFloat = 1 << 53
+
+// canonicalize converts the value to its canonical form when Config.Canonical is enabled.
+// Structs and maps are converted to maps so that the keys are sorted by the encoders, numbers are normalized
+// (integral floats such as 1.0 or 1e3 are rendered as integers) and lists of objects are sorted by Config.SortListsBy when set.
+// This makes the output byte-identical for equivalent values in both YAML and JSON.
+func (cfg *Config) canonicalize(value any) (any, error) {
+	if !cfg.Canonical {
+		return value, nil
+	}
+
+	cfg.logger.Debug("converting the value to canonical form since Config.Canonical is enabled")
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(valueJSON))
+	decoder.UseNumber()
+
+	var generic any
+	if err = decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return canonicalValue(generic, cfg.SortListsBy), nil
+}
+
+func canonicalValue(value any, sortBy string) any {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for key, element := range typedValue {
+			typedValue[key] = canonicalValue(element, sortBy)
+		}
+
+		return typedValue
+	case []any:
+		for index, element := range typedValue {
+			typedValue[index] = canonicalValue(element, sortBy)
+		}
+
+		if len(sortBy) != 0 && isListOfObjects(typedValue, sortBy) {
+			sort.SliceStable(typedValue, func(i, j int) bool {
+				left := typedValue[i].(map[string]any)[sortBy]
+				right := typedValue[j].(map[string]any)[sortBy]
+
+				return compareValues(queryValue(left), queryValue(right)) < 0
+			})
+		}
+
+		return typedValue
+	case json.Number:
+		return canonicalNumber(typedValue)
+	default:
+		return value
+	}
+}
+
+// canonicalNumber returns integral numbers as int64 and the rest as float64.
+func canonicalNumber(number json.Number) any {
+	if integer, err := number.Int64(); err == nil {
+		return integer
+	}
+
+	float, err := number.Float64()
+	if err != nil {
+		return number.String()
+	}
+
+	if float == math.Trunc(float) && math.Abs(float) < maxExactFloat {
+		return int64(float)
+	}
+
+	return float
+}
+
+func isListOfObjects(list []any, key string) bool {
+	for _, element := range list {
+		object, ok := element.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		if _, ok = object[key]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// queryValue converts the canonical numbers to float64 so that they could be compared with compareValues.
+func queryValue(value any) any {
+	if integer, ok := value.(int64); ok {
+		return float64(integer)
+	}
+
+	return value
+}
@@ -0,0 +1,78 @@
+package renderer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Canonical(t *testing.T) {
+	type job struct {
+		Timeout float64 `json:"timeout"`
+		Name    string  `json:"name"`
+	}
+
+	type stage struct {
+		Name string `json:"name"`
+		Jobs []job  `json:"jobs"`
+	}
+
+	structValue := stage{Name: "build", Jobs: []job{{Name: "test", Timeout: 1e3}, {Name: "compile", Timeout: 2.5}}}
+	mapValue := map[string]any{
+		"name": "build",
+		"jobs": []any{
+			map[string]any{"timeout": 2.5, "name": "compile"},
+			map[string]any{"name": "test", "timeout": 1000},
+		},
+	}
+
+	render := func(t *testing.T, yaml, json bool, value any) string {
+		t.Helper()
+
+		strReader := new(bytes.Buffer)
+
+		config := renderer.GetRenderer(strReader, logrus.New(), true, yaml, json, false, false)
+		config.Canonical = true
+		config.SortListsBy = "name"
+
+		require.NoError(t, config.Render(value))
+
+		return strReader.String()
+	}
+
+	t.Run("should render equivalent values byte-identical in json", func(t *testing.T) {
+		expected := render(t, false, true, mapValue)
+
+		assert.Equal(t, expected, render(t, false, true, structValue))
+		assert.Equal(t, `{
+     "jobs": [
+          {
+               "name": "compile",
+               "timeout": 2.5
+          },
+          {
+               "name": "test",
+               "timeout": 1000
+          }
+     ],
+     "name": "build"
+}`, expected)
+	})
+
+	t.Run("should render equivalent values byte-identical in yaml", func(t *testing.T) {
+		expected := render(t, true, false, mapValue)
+
+		assert.Equal(t, expected, render(t, true, false, structValue))
+		assert.Equal(t, "---\njobs:\n  - name: compile\n    timeout: 2.5\n  - name: test\n    timeout: 1000\nname: build\n", expected)
+	})
+
+	t.Run("should not sort lists with elements missing the key", func(t *testing.T) {
+		value := []any{map[string]any{"name": "b"}, map[string]any{"id": "a"}}
+
+		assert.JSONEq(t, `[{"name":"b"},{"id":"a"}]`, render(t, false, true, value))
+	})
+}
@@ -17,10 +17,35 @@ const (
 	TypeYAML = "yaml"
 	// TypeJSON identifies JSON content for syntax highlighting.
 	TypeJSON = "json"
+	// TypeCSV identifies CSV content for syntax highlighting.
+	TypeCSV = "csv"
+	// TypeTable identifies table content for syntax highlighting.
+	TypeTable = "table"
+	// TypeTOML identifies TOML content for syntax highlighting.
+	TypeTOML = "toml"
+	// TypeXML identifies XML content for syntax highlighting.
+	TypeXML = "xml"
+	// TypeHCL identifies HCL content for syntax highlighting.
+	TypeHCL = "hcl"
+	// TypeShell identifies shell scripts for syntax highlighting.
+	TypeShell = "shell"
 )
 
 // Color add colors to your YAML, JSON or any specified string.
+// CSV and table content are colored by column and header respectively, every other type is handed to the matching lexer.
 func (cfg *Config) Color(contentType, yamlContent string) (string, error) {
+	switch contentType {
+	case TypeCSV:
+		delimiter, err := cfg.CSVOptions.delimiter()
+		if err != nil {
+			return "", err
+		}
+
+		return colorCSV(yamlContent, delimiter), nil
+	case TypeTable:
+		return colorTable(yamlContent, true), nil
+	}
+
 	lexer := lexers.Get(contentType)
 	if lexer == nil {
 		return "", &errors.CommonError{Message: fmt.Sprintf("no lexer found for '%s'", contentType)}
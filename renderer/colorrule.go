@@ -0,0 +1,149 @@
+package renderer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/nikhilsbhat/common/internal/terminal"
+	"github.com/olekukonko/tablewriter"
+)
+
+// tableColors maps the color names supported by ColorRule to the colors of tablewriter.
+var tableColors = map[string]int{
+	"black":   tablewriter.FgBlackColor,
+	"red":     tablewriter.FgRedColor,
+	"green":   tablewriter.FgGreenColor,
+	"yellow":  tablewriter.FgYellowColor,
+	"blue":    tablewriter.FgBlueColor,
+	"magenta": tablewriter.FgMagentaColor,
+	"cyan":    tablewriter.FgCyanColor,
+	"white":   tablewriter.FgWhiteColor,
+	"bold":    tablewriter.Bold,
+}
+
+// ColorRule colors the table cells of Column that match Value exactly or Pattern as a regular expression, ex: Failed in red.
+// Rules are applied in order, where the first matching rule wins and rules for cells take precedence over rules for rows.
+type ColorRule struct {
+	// Column is the header of the column matched, as identified by ToTable, compared case-insensitively since tablewriter upper-cases the headers.
+	Column string `json:"column,omitempty" yaml:"column,omitempty"`
+	// Value matches the cells equal to it.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Pattern matches the cells with the regular expression, used when Value is not set.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Color is one of black, red, green, yellow, blue, magenta, cyan, white or bold.
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
+	// Row colors the entire row instead of the matching cell.
+	Row bool `json:"row,omitempty" yaml:"row,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Match reports whether the cell of the column matches the rule, invalid patterns never match.
+func (rule ColorRule) Match(column, value string) bool {
+	if !strings.EqualFold(rule.Column, column) {
+		return false
+	}
+
+	if len(rule.Value) != 0 || len(rule.Pattern) == 0 {
+		return rule.Value == value
+	}
+
+	if rule.pattern != nil {
+		return rule.pattern.MatchString(value)
+	}
+
+	matched, err := regexp.MatchString(rule.Pattern, value)
+
+	return err == nil && matched
+}
+
+// compile checks the color of the rule and returns it with its pattern compiled, so that it is not compiled again for every cell.
+func (rule ColorRule) compile() (ColorRule, error) {
+	if _, ok := tableColors[strings.ToLower(rule.Color)]; !ok {
+		return rule, &errors.CommonError{Message: fmt.Sprintf("unknown color '%s' in color rule for column '%s'", rule.Color, rule.Column)}
+	}
+
+	if len(rule.Value) == 0 && len(rule.Pattern) != 0 {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return rule, &errors.CommonError{Message: fmt.Sprintf("invalid pattern '%s' in color rule for column '%s': %v", rule.Pattern, rule.Column, err)}
+		}
+
+		rule.pattern = pattern
+	}
+
+	return rule, nil
+}
+
+// colorRules returns Config.ColorRules compiled when they should be applied, which is only when colors are enabled and the output is a terminal.
+func (cfg *Config) colorRules() ([]ColorRule, error) {
+	if len(cfg.ColorRules) == 0 {
+		return nil, nil
+	}
+
+	rules := make([]ColorRule, 0, len(cfg.ColorRules))
+
+	for _, rule := range cfg.ColorRules {
+		compiled, err := rule.compile()
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, compiled)
+	}
+
+	if cfg.NoColor || !terminal.IsTerminal(cfg.output) {
+		cfg.logger.Debug("skipping color rules since colors are disabled or output is not a terminal")
+
+		return nil, nil
+	}
+
+	return rules, nil
+}
+
+// cellColors returns the colors of the cells of a table row from the rules, nil when none of the rules match.
+func cellColors(rules []ColorRule, headers, cells []string) []tablewriter.Colors {
+	var rowColor tablewriter.Colors
+
+	colors := make([]tablewriter.Colors, len(cells))
+	matched := false
+
+	for index, cell := range cells {
+		if index >= len(headers) {
+			break
+		}
+
+		for _, rule := range rules {
+			if !rule.Match(headers[index], cell) {
+				continue
+			}
+
+			matched = true
+			ruleColor := tablewriter.Colors{tableColors[strings.ToLower(rule.Color)]}
+
+			if !rule.Row {
+				colors[index] = ruleColor
+
+				break
+			}
+
+			if rowColor == nil {
+				rowColor = ruleColor
+			}
+		}
+	}
+
+	if !matched {
+		return nil
+	}
+
+	for index := range colors {
+		if colors[index] == nil {
+			colors[index] = rowColor
+		}
+	}
+
+	return colors
+}
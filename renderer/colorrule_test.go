@@ -0,0 +1,90 @@
+package renderer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorRule_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     renderer.ColorRule
+		column   string
+		value    string
+		expected bool
+	}{
+		{name: "exact value", rule: renderer.ColorRule{Column: "status", Value: "Failed"}, column: "status", value: "Failed", expected: true},
+		{name: "different value", rule: renderer.ColorRule{Column: "status", Value: "Failed"}, column: "status", value: "Passed", expected: false},
+		{name: "upper-cased column", rule: renderer.ColorRule{Column: "status", Value: "Failed"}, column: "STATUS", value: "Failed", expected: true},
+		{name: "different column", rule: renderer.ColorRule{Column: "status", Value: "Failed"}, column: "name", value: "Failed", expected: false},
+		{name: "pattern", rule: renderer.ColorRule{Column: "status", Pattern: "^(Building|Scheduled)$"}, column: "status", value: "Building", expected: true},
+		{name: "partial pattern", rule: renderer.ColorRule{Column: "status", Pattern: "Build"}, column: "status", value: "Building", expected: true},
+		{name: "value takes precedence", rule: renderer.ColorRule{Column: "status", Value: "Build", Pattern: "Build"}, column: "status", value: "Building", expected: false},
+		{name: "invalid pattern", rule: renderer.ColorRule{Column: "status", Pattern: "(Build"}, column: "status", value: "(Build", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.rule.Match(test.column, test.value))
+		})
+	}
+}
+
+func TestConfig_ColorRules(t *testing.T) {
+	rules := []renderer.ColorRule{
+		{Column: "status", Value: "Failed", Color: "red", Row: true},
+		{Column: "status", Value: "Passed", Color: "green"},
+		{Column: "status", Pattern: "^Build", Color: "yellow"},
+	}
+
+	t.Run("should render plain table when output is not a terminal", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, true)
+		render.ColorRules = rules
+
+		err := render.Render(pipelineStatuses["pipelines"])
+		require.NoError(t, err)
+		assert.Contains(t, strReader.String(), "Failed")
+		assert.NotContains(t, strReader.String(), "\x1b[")
+	})
+
+	t.Run("should error for unknown color", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, false, false, true)
+		render.ColorRules = []renderer.ColorRule{{Column: "status", Value: "Failed", Color: "crimson"}}
+
+		err := render.Render(pipelineStatuses["pipelines"])
+		require.EqualError(t, err, "unknown color 'crimson' in color rule for column 'status'")
+	})
+
+	t.Run("should error for invalid pattern", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, false, false, true)
+		render.ColorRules = []renderer.ColorRule{{Column: "status", Pattern: "(Failed", Color: "red"}}
+
+		err := render.Render(pipelineStatuses["pipelines"])
+		require.ErrorContains(t, err, "invalid pattern '(Failed' in color rule for column 'status'")
+	})
+
+	t.Run("should validate the rules for rows of strings", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, true)
+		render.ColorRules = []renderer.ColorRule{{Column: "status", Pattern: "(Failed", Color: "red"}}
+
+		rows := [][]string{{"name", "status"}, {"build", "Failed"}}
+
+		err := render.Render(rows)
+		require.ErrorContains(t, err, "invalid pattern '(Failed' in color rule for column 'status'")
+
+		render.ColorRules = rules
+
+		err = render.Render(rows)
+		require.NoError(t, err)
+		assert.Equal(t, "+-------+--------+\n| name  | status |\n+-------+--------+\n| build | Failed |\n+-------+--------+\n", strReader.String())
+	})
+}
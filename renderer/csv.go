@@ -0,0 +1,133 @@
+package renderer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nikhilsbhat/common/errors"
+)
+
+const (
+	// CSVDelimiterComma separates the CSV fields with comma, this is the default.
+	CSVDelimiterComma = ","
+	// CSVDelimiterTab separates the CSV fields with tab, producing TSV.
+	CSVDelimiterTab = "\t"
+	// CSVDelimiterSemicolon separates the CSV fields with semicolon.
+	CSVDelimiterSemicolon = ";"
+	// CSVQuoteMinimal quotes only the fields that require it, this is the default.
+	CSVQuoteMinimal = "minimal"
+	// CSVQuoteAll quotes every field.
+	CSVQuoteAll = "all"
+	// CSVQuoteNone never quotes the fields, rendering the fields containing the delimiter, a quote or a newline fails.
+	CSVQuoteNone = "none"
+)
+
+// CSVOptions holds the options to customise the CSV rendered by ToCSV.
+type CSVOptions struct {
+	// Delimiter separates the fields, one of CSVDelimiterComma, CSVDelimiterTab or CSVDelimiterSemicolon.
+	Delimiter string `json:"delimiter,omitempty" yaml:"delimiter,omitempty"`
+	// Quote is the quoting policy, one of CSVQuoteMinimal, CSVQuoteAll or CSVQuoteNone.
+	Quote string `json:"quote,omitempty" yaml:"quote,omitempty"`
+	// NoHeader skips the header row.
+	NoHeader bool `json:"no_header,omitempty" yaml:"no_header,omitempty"`
+	// Columns selects the columns to be rendered and their order.
+	Columns []string `json:"columns,omitempty" yaml:"columns,omitempty"`
+	// Flatten renders the nested structs and maps as dotted columns, ex: spec.image.
+	Flatten bool `json:"flatten,omitempty" yaml:"flatten,omitempty"`
+	// SliceSeparator joins the elements of slices in a single field, defaults to '|'.
+	SliceSeparator string `json:"slice_separator,omitempty" yaml:"slice_separator,omitempty"`
+}
+
+func (opts CSVOptions) isDefault() bool {
+	return (opts.Delimiter == "" || opts.Delimiter == CSVDelimiterComma) &&
+		(opts.Quote == "" || opts.Quote == CSVQuoteMinimal) &&
+		!opts.NoHeader && !opts.Flatten &&
+		len(opts.Columns) == 0 && len(opts.SliceSeparator) == 0
+}
+
+func (opts CSVOptions) delimiter() (rune, error) {
+	if len(opts.Delimiter) == 0 {
+		return ',', nil
+	}
+
+	delimiter, size := utf8.DecodeRuneInString(opts.Delimiter)
+	if size != len(opts.Delimiter) || delimiter == '"' || delimiter == '\n' || delimiter == '\r' {
+		return 0, &errors.CommonError{Message: fmt.Sprintf("invalid csv delimiter '%s'", opts.Delimiter)}
+	}
+
+	return delimiter, nil
+}
+
+func (opts CSVOptions) sliceSeparator() string {
+	if len(opts.SliceSeparator) == 0 {
+		return defaultSliceSeparator
+	}
+
+	return opts.SliceSeparator
+}
+
+// csvString renders the value as CSV honouring the Config.CSVOptions.
+func (cfg *Config) csvString(value any) (string, error) {
+	opts := cfg.CSVOptions
+
+	delimiter, err := opts.delimiter()
+	if err != nil {
+		return "", err
+	}
+
+	rec := tabulate(value, opts.Flatten, cfg.MapAsRows)
+	rec.selectColumns(opts.Columns)
+
+	if err = cfg.formatRecords(rec); err != nil {
+		return "", err
+	}
+
+	lines := make([][]string, 0, len(rec.rows)+1)
+	if !opts.NoHeader {
+		lines = append(lines, rec.headers)
+	}
+
+	for _, row := range rec.rows {
+		lines = append(lines, rec.cells(row, opts.sliceSeparator()))
+	}
+
+	return writeCSV(lines, delimiter, opts.Quote)
+}
+
+func writeCSV(lines [][]string, delimiter rune, quote string) (string, error) {
+	var csvString strings.Builder
+
+	switch quote {
+	case "", CSVQuoteMinimal:
+		csvWriter := csv.NewWriter(&csvString)
+		csvWriter.Comma = delimiter
+
+		if err := csvWriter.WriteAll(lines); err != nil {
+			return "", err
+		}
+	case CSVQuoteAll, CSVQuoteNone:
+		for _, line := range lines {
+			fields := make([]string, 0, len(line))
+			for _, field := range line {
+				switch {
+				case quote == CSVQuoteAll:
+					field = `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+				case strings.ContainsRune(field, delimiter) || strings.ContainsAny(field, "\"\r\n"):
+					return "", &errors.CommonError{
+						Message: fmt.Sprintf("cannot render the csv field '%s' without quoting, it contains the delimiter, a quote or a newline", field),
+					}
+				}
+
+				fields = append(fields, field)
+			}
+
+			csvString.WriteString(strings.Join(fields, string(delimiter)) + "\n")
+		}
+	default:
+		return "", &errors.CommonError{Message: fmt.Sprintf("invalid csv quoting policy '%s'", quote)}
+	}
+
+	return csvString.String(), nil
+}
@@ -0,0 +1,223 @@
+package renderer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type container struct {
+	Image string   `json:"image"`
+	Ports []int    `json:"ports"`
+	Args  []string `json:"args,omitempty"`
+}
+
+type deployment struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+	Spec   container         `json:"spec"`
+	secret string
+}
+
+func TestConfig_ToCSV(t *testing.T) {
+	deployments := []deployment{
+		{
+			Name:   "api",
+			Labels: map[string]string{"tier": "backend", "app": "api"},
+			Spec:   container{Image: "api:v1", Ports: []int{80, 443}, Args: []string{"--debug"}},
+			secret: "hidden",
+		},
+		{
+			Name:   "web, frontend",
+			Labels: map[string]string{"app": "web"},
+			Spec:   container{Image: "web:v2", Ports: []int{8080}},
+		},
+	}
+
+	t.Run("should flatten nested structs and maps to dotted columns", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.CSVOptions = renderer.CSVOptions{Flatten: true}
+
+		err := render.Render(deployments)
+		require.NoError(t, err)
+		assert.Equal(t, "name,labels.app,labels.tier,spec.image,spec.ports,spec.args\n"+
+			"api,api,backend,api:v1,80|443,--debug\n"+
+			"\"web, frontend\",web,,web:v2,8080,\n", strReader.String())
+		assert.NotContains(t, strReader.String(), "hidden")
+	})
+
+	t.Run("should render tsv with selected columns and custom slice separator", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.CSVOptions = renderer.CSVOptions{
+			Delimiter:      renderer.CSVDelimiterTab,
+			Columns:        []string{"spec.ports", "name"},
+			Flatten:        true,
+			SliceSeparator: ";",
+		}
+
+		err := render.Render(deployments)
+		require.NoError(t, err)
+		assert.Equal(t, "spec.ports\tname\n80;443\tapi\n8080\tweb, frontend\n", strReader.String())
+	})
+
+	t.Run("should quote all fields and skip the header", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.CSVOptions = renderer.CSVOptions{
+			Delimiter: renderer.CSVDelimiterSemicolon,
+			Quote:     renderer.CSVQuoteAll,
+			NoHeader:  true,
+			Columns:   []string{"name"},
+		}
+
+		err := render.Render(deployments)
+		require.NoError(t, err)
+		assert.Equal(t, "\"api\"\n\"web, frontend\"\n", strReader.String())
+	})
+
+	t.Run("should not quote the fields when quoting is disabled", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.CSVOptions = renderer.CSVOptions{
+			Delimiter: renderer.CSVDelimiterTab,
+			Quote:     renderer.CSVQuoteNone,
+			Columns:   []string{"name", "spec.image"},
+			Flatten:   true,
+		}
+
+		err := render.Render(deployments)
+		require.NoError(t, err)
+		assert.Equal(t, "name\tspec.image\napi\tapi:v1\nweb, frontend\tweb:v2\n", strReader.String())
+	})
+
+	t.Run("should error for the fields requiring quotes when quoting is disabled", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, false, true, false)
+		render.CSVOptions = renderer.CSVOptions{Quote: renderer.CSVQuoteNone, Columns: []string{"name"}}
+
+		err := render.Render(deployments)
+		require.EqualError(t, err, "cannot render the csv field 'web, frontend' without quoting, it contains the delimiter, a quote or a newline")
+	})
+
+	t.Run("should name the columns the same way with and without options", func(t *testing.T) {
+		type person struct {
+			Name  string `json:"name"`
+			Age   int    `json:"age"`
+			Email string `csv:"mail" json:"email"`
+		}
+
+		people := []person{{Name: "ana", Age: 30, Email: "ana@example.com"}}
+
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		err := render.Render(people)
+		require.NoError(t, err)
+		assert.Equal(t, "name,age,mail\nana,30,ana@example.com\n", strReader.String())
+
+		strReader.Reset()
+
+		render.CSVOptions = renderer.CSVOptions{Delimiter: renderer.CSVDelimiterSemicolon}
+
+		err = render.Render(people)
+		require.NoError(t, err)
+		assert.Equal(t, "name;age;mail\nana;30;ana@example.com\n", strReader.String())
+	})
+
+	t.Run("should error for invalid delimiter", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, false, true, false)
+		render.CSVOptions = renderer.CSVOptions{Delimiter: "::"}
+
+		err := render.Render(deployments)
+		require.EqualError(t, err, "invalid csv delimiter '::'")
+	})
+
+	t.Run("should error for invalid quoting policy", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, false, true, false)
+		render.CSVOptions = renderer.CSVOptions{Quote: "sometimes"}
+
+		err := render.Render(deployments)
+		require.EqualError(t, err, "invalid csv quoting policy 'sometimes'")
+	})
+}
+
+func TestConfig_ToCSV_values(t *testing.T) {
+	t.Run("should render a single struct as key/value rows", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		err := render.Render(container{Image: "api:v1", Ports: []int{80, 443}})
+		require.NoError(t, err)
+		assert.Equal(t, "key,value\nimage,api:v1\nports,80|443\nargs,\n", strReader.String())
+	})
+
+	t.Run("should render a map with keys as columns", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		err := render.Render(map[string]any{"name": "api", "replicas": 2, "enabled": true})
+		require.NoError(t, err)
+		assert.Equal(t, "enabled,name,replicas\ntrue,api,2\n", strReader.String())
+	})
+
+	t.Run("should render a map with keys as rows", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.MapAsRows = true
+
+		err := render.Render(map[string]any{"name": "api", "replicas": 2})
+		require.NoError(t, err)
+		assert.Equal(t, "key,value\nname,api\nreplicas,2\n", strReader.String())
+	})
+
+	t.Run("should render slice of maps with union of keys as headers", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		err := render.Render([]map[string]any{
+			{"name": "api", "replicas": 2},
+			{"name": "web", "image": "web:v2"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "name,replicas,image\napi,2,\nweb,,web:v2\n", strReader.String())
+	})
+
+	t.Run("should render slice of mixed values", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		err := render.Render([]any{
+			map[string]any{"name": "api"},
+			container{Image: "web:v2"},
+			"plain",
+			nil,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "name,image,ports,args,value\napi,,,,\n,web:v2,,,\n,,,,plain\n,,,,\n", strReader.String())
+	})
+
+	t.Run("should render a scalar under the value column", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		err := render.Render(3.5)
+		require.NoError(t, err)
+		assert.Equal(t, "value\n3.5\n", strReader.String())
+	})
+}
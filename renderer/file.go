@@ -0,0 +1,161 @@
+package renderer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nikhilsbhat/common/errors"
+)
+
+const (
+	defaultFilePermission = 0o644
+	defaultBackupSuffix   = ".bak"
+)
+
+// fileFormats maps the file extensions to the format used by RenderToFile.
+var fileFormats = map[string]string{
+	".yaml": TypeYAML,
+	".yml":  TypeYAML,
+	".json": TypeJSON,
+	".csv":  TypeCSV,
+	".toml": TypeTOML,
+	".xml":  TypeXML,
+}
+
+// FileOptions holds the options used by RenderToFile.
+type FileOptions struct {
+	// Backup keeps a copy of the existing file before it is replaced.
+	Backup bool `json:"backup,omitempty" yaml:"backup,omitempty"`
+	// BackupSuffix is appended to the path of the backup file, defaults to '.bak'.
+	BackupSuffix string `json:"backup_suffix,omitempty" yaml:"backup_suffix,omitempty"`
+	// Permission of the newly created files, defaults to 0644. Existing files retain their permissions.
+	Permission os.FileMode `json:"permission,omitempty" yaml:"permission,omitempty"`
+}
+
+// RenderToFile renders the value to the file at path, in the format identified from the file extension (.yaml, .yml, .json, .csv, .toml, .xml).
+// Colors are never written to files, and the file is written atomically by renaming a temporary file over it,
+// so a failure never leaves a half-written file behind. Symbolic links are retained, the file they link to is replaced instead.
+func (cfg *Config) RenderToFile(path string, value any) error {
+	format, ok := fileFormats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return &errors.CommonError{Message: fmt.Sprintf("cannot identify the format of file '%s' from its extension", path)}
+	}
+
+	cfg.logger.Debugf("rendering output to file '%s' in format '%s'", path, format)
+
+	permission := cfg.File.Permission
+	if permission == 0 {
+		permission = defaultFilePermission
+	}
+
+	target, err := resolveSymlinks(path)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(target)
+
+	exists := err == nil
+	if exists {
+		permission = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(target), fmt.Sprintf(".%s.*.tmp", filepath.Base(target)))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// removing the temporary file fails once it is renamed, which is expected.
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	if err = cfg.renderToTempFile(tempFile, format, value); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tempFile.Name(), permission); err != nil {
+		return err
+	}
+
+	if exists && cfg.File.Backup {
+		if err = copyFile(path, path+cfg.backupSuffix(), permission); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tempFile.Name(), target)
+}
+
+// resolveSymlinks returns the path of the file the symbolic links at path lead to, the path is returned as is when it does not exist yet.
+func resolveSymlinks(path string) (string, error) {
+	target, err := filepath.EvalSymlinks(path)
+	if os.IsNotExist(err) {
+		return path, nil
+	}
+
+	return target, err
+}
+
+func (cfg *Config) renderToTempFile(tempFile *os.File, format string, value any) error {
+	config := *cfg
+	config.NoColor = true
+	config.Pager = false
+	config.progress = nil
+	config.output = tempFile
+	config.writer = bufio.NewWriter(tempFile)
+
+	if err := config.SetFormat(format); err != nil {
+		return err
+	}
+
+	if err := config.Render(value); err != nil {
+		_ = tempFile.Close()
+
+		return err
+	}
+
+	if err := tempFile.Sync(); err != nil {
+		_ = tempFile.Close()
+
+		return err
+	}
+
+	return tempFile.Close()
+}
+
+func (cfg *Config) backupSuffix() string {
+	if len(cfg.File.BackupSuffix) == 0 {
+		return defaultBackupSuffix
+	}
+
+	return cfg.File.BackupSuffix
+}
+
+func copyFile(source, destination string, permission os.FileMode) error {
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+
+	defer sourceFile.Close()
+
+	destinationFile, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, permission)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(destinationFile, sourceFile); err != nil {
+		_ = destinationFile.Close()
+
+		return err
+	}
+
+	return destinationFile.Close()
+}
@@ -0,0 +1,107 @@
+package renderer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_RenderToFile(t *testing.T) {
+	value := map[string]any{"pipeline": map[string]any{"name": "build", "counter": 10}}
+
+	t.Run("should infer the format from the extension without colors", func(t *testing.T) {
+		dir := t.TempDir()
+		render := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+
+		for path, expected := range map[string]string{
+			"config.json": "{\n     \"pipeline\": {\n          \"counter\": 10,\n          \"name\": \"build\"\n     }\n}",
+			"config.yml":  "---\npipeline:\n  counter: 10\n  name: build\n",
+			"config.toml": "[pipeline]\n  counter = 10\n  name = \"build\"\n",
+		} {
+			err := render.RenderToFile(filepath.Join(dir, path), value)
+			require.NoError(t, err)
+
+			actual, err := os.ReadFile(filepath.Join(dir, path))
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(actual), path)
+		}
+	})
+
+	t.Run("should preserve permissions and keep a backup of the existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("old: content\n"), 0o600))
+
+		render := renderer.GetRenderer(nil, logrus.New(), true, false, false, false, false)
+		render.File = renderer.FileOptions{Backup: true}
+
+		err := render.RenderToFile(path, value)
+		require.NoError(t, err)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		backup, err := os.ReadFile(path + ".bak")
+		require.NoError(t, err)
+		assert.Equal(t, "old: content\n", string(backup))
+	})
+
+	t.Run("should replace the file the symbolic link leads to retaining the link", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "configs", "pipeline.yaml")
+		link := filepath.Join(dir, "config.yaml")
+
+		require.NoError(t, os.Mkdir(filepath.Dir(target), 0o755))
+		require.NoError(t, os.WriteFile(target, []byte("old: content\n"), 0o600))
+		require.NoError(t, os.Symlink(target, link))
+
+		render := renderer.GetRenderer(nil, logrus.New(), true, false, false, false, false)
+
+		err := render.RenderToFile(link, value)
+		require.NoError(t, err)
+
+		info, err := os.Lstat(link)
+		require.NoError(t, err)
+		assert.Equal(t, os.ModeSymlink, info.Mode().Type())
+
+		actual, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, "---\npipeline:\n  counter: 10\n  name: build\n", string(actual))
+
+		info, err = os.Stat(target)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("should leave the existing file untouched when rendering fails", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"old": "content"}`), 0o600))
+
+		render := renderer.GetRenderer(nil, logrus.New(), true, false, false, false, false)
+
+		err := render.RenderToFile(path, map[string]any{"invalid": func() {}})
+		require.Error(t, err)
+
+		actual, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"old": "content"}`, string(actual))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("should error for unknown extension", func(t *testing.T) {
+		render := renderer.GetRenderer(nil, logrus.New(), true, false, false, false, false)
+
+		err := render.RenderToFile(filepath.Join(t.TempDir(), "config.ini"), value)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot identify the format of file")
+	})
+}
@@ -0,0 +1,307 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nikhilsbhat/common/errors"
+)
+
+const (
+	// FormatAge renders timestamps relative to now, ex: 5m ago.
+	FormatAge = "age"
+	// FormatSize renders byte counts in IEC units, ex: 1.2 GiB.
+	FormatSize = "size"
+	// FormatDuration renders durations rounded to a second, or to the precision passed as in 'duration:1m'.
+	// Numbers are read as nanoseconds, which is how time.Duration is encoded in JSON and so rendered after Config.Query or in canonical form.
+	FormatDuration = "duration"
+	// FormatSeconds renders numbers of seconds as durations, ex: 90 as 1m30s, rounded as in FormatDuration.
+	FormatSeconds = "seconds"
+	// FormatBool renders booleans as ✓ and ✗.
+	FormatBool = "bool"
+	// FormatTime renders timestamps in the local time zone, or in the time zone passed as in 'time:Asia/Kolkata'.
+	FormatTime = "time"
+
+	// formatTag is the struct tag that selects the format of a field, ex: `render:"age"`.
+	formatTag    = "render"
+	sizeUnit     = 1024
+	sizeUnits    = "KMGTPE"
+	hoursPerDay  = 24
+	daysPerYear  = 365
+	formatTrue   = "✓"
+	formatFalse  = "✗"
+	formatArgSep = ":"
+)
+
+// cellFormatter converts the raw value of a cell to its human-friendly form, ok is false when the value is not supported by the formatter.
+type cellFormatter func(value any) (formatted string, ok bool)
+
+// parseFormat returns the formatter for specifications of form 'name' or 'name:argument',
+// see FormatAge, FormatSize, FormatDuration, FormatSeconds, FormatBool and FormatTime.
+func parseFormat(spec string) (cellFormatter, error) {
+	name, argument, _ := strings.Cut(spec, formatArgSep)
+
+	switch name {
+	case FormatAge:
+		return formatAge(time.Now), nil
+	case FormatSize:
+		return formatSize, nil
+	case FormatDuration, FormatSeconds:
+		precision := time.Second
+
+		if len(argument) != 0 {
+			parsed, err := time.ParseDuration(argument)
+			if err != nil {
+				return nil, &errors.CommonError{Message: fmt.Sprintf("invalid precision '%s' for format '%s': %v", argument, name, err)}
+			}
+
+			precision = parsed
+		}
+
+		unit := time.Nanosecond
+		if name == FormatSeconds {
+			unit = time.Second
+		}
+
+		return formatDuration(precision, unit), nil
+	case FormatBool:
+		return formatBool, nil
+	case FormatTime:
+		location := time.Local
+
+		if len(argument) != 0 {
+			loaded, err := time.LoadLocation(argument)
+			if err != nil {
+				return nil, &errors.CommonError{Message: fmt.Sprintf("invalid time zone '%s' for format '%s': %v", argument, name, err)}
+			}
+
+			location = loaded
+		}
+
+		return formatTime(location), nil
+	default:
+		return nil, &errors.CommonError{
+			Message: fmt.Sprintf("unknown format '%s', should be one of '%s', '%s', '%s', '%s', '%s' or '%s'",
+				spec, FormatAge, FormatSize, FormatDuration, FormatSeconds, FormatBool, FormatTime),
+		}
+	}
+}
+
+// formatRecords sets the formatters of the records from the `render` struct tags and Config.Formats, where Config.Formats takes precedence.
+func (cfg *Config) formatRecords(rec *records) error {
+	specs := make(map[string]string, len(rec.formats)+len(cfg.Formats))
+	for column, spec := range rec.formats {
+		specs[column] = spec
+	}
+
+	for column, spec := range cfg.Formats {
+		specs[column] = spec
+	}
+
+	rec.formatters = make(map[string]cellFormatter, len(specs))
+
+	for column, spec := range specs {
+		formatter, err := parseFormat(spec)
+		if err != nil {
+			return &errors.CommonError{Message: fmt.Sprintf("column '%s': %v", column, err)}
+		}
+
+		rec.formatters[column] = formatter
+	}
+
+	return nil
+}
+
+// hasFormats reports whether the value should be formatted, either from Config.Formats or from the `render` struct tags.
+func (cfg *Config) hasFormats(value any) bool {
+	return len(cfg.Formats) != 0 || hasFormatTags(reflect.TypeOf(value), make(map[reflect.Type]bool))
+}
+
+func hasFormatTags(valueType reflect.Type, visited map[reflect.Type]bool) bool {
+	for valueType != nil && (valueType.Kind() == reflect.Pointer || valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Array) {
+		valueType = valueType.Elem()
+	}
+
+	if valueType == nil || valueType.Kind() != reflect.Struct || visited[valueType] {
+		return false
+	}
+
+	visited[valueType] = true
+
+	for index := range valueType.NumField() {
+		field := valueType.Field(index)
+		if _, ok := field.Tag.Lookup(formatTag); ok {
+			return true
+		}
+
+		if field.IsExported() && hasFormatTags(field.Type, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatAge(now func() time.Time) cellFormatter {
+	return func(value any) (string, bool) {
+		timestamp, ok := asTime(value)
+		if !ok {
+			return "", false
+		}
+
+		age := now().Sub(timestamp)
+		if age < 0 {
+			return "in " + humanDuration(-age), true
+		}
+
+		return humanDuration(age) + " ago", true
+	}
+}
+
+// humanDuration returns the duration in its largest unit, ex: 3d.
+func humanDuration(duration time.Duration) string {
+	switch {
+	case duration < time.Minute:
+		return fmt.Sprintf("%ds", int(duration.Seconds()))
+	case duration < time.Hour:
+		return fmt.Sprintf("%dm", int(duration.Minutes()))
+	case duration < hoursPerDay*time.Hour:
+		return fmt.Sprintf("%dh", int(duration.Hours()))
+	case duration < daysPerYear*hoursPerDay*time.Hour:
+		return fmt.Sprintf("%dd", int(duration.Hours()/hoursPerDay))
+	default:
+		return fmt.Sprintf("%dy", int(duration.Hours()/(hoursPerDay*daysPerYear)))
+	}
+}
+
+func formatSize(value any) (string, bool) {
+	size, ok := asFloat(value)
+	if !ok {
+		return "", false
+	}
+
+	if math.Abs(size) < sizeUnit {
+		return fmt.Sprintf("%d B", int64(size)), true
+	}
+
+	exponent := 0
+	for size = size / sizeUnit; math.Abs(size) >= sizeUnit && exponent < len(sizeUnits)-1; size /= sizeUnit {
+		exponent++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", size, sizeUnits[exponent]), true
+}
+
+// formatDuration formats durations, strings such as '1m30s' and numbers in the unit passed.
+func formatDuration(precision, unit time.Duration) cellFormatter {
+	return func(value any) (string, bool) {
+		var duration time.Duration
+
+		switch typedValue := value.(type) {
+		case time.Duration:
+			duration = typedValue
+		case string:
+			parsed, err := time.ParseDuration(typedValue)
+			if err != nil {
+				return "", false
+			}
+
+			duration = parsed
+		default:
+			number, ok := asFloat(value)
+			if !ok {
+				return "", false
+			}
+
+			duration = time.Duration(number * float64(unit))
+		}
+
+		return duration.Round(precision).String(), true
+	}
+}
+
+func formatBool(value any) (string, bool) {
+	var boolean bool
+
+	switch typedValue := value.(type) {
+	case bool:
+		boolean = typedValue
+	case string:
+		parsed, err := strconv.ParseBool(typedValue)
+		if err != nil {
+			return "", false
+		}
+
+		boolean = parsed
+	default:
+		return "", false
+	}
+
+	if boolean {
+		return formatTrue, true
+	}
+
+	return formatFalse, true
+}
+
+func formatTime(location *time.Location) cellFormatter {
+	return func(value any) (string, bool) {
+		timestamp, ok := asTime(value)
+		if !ok {
+			return "", false
+		}
+
+		return timestamp.In(location).Format(time.RFC3339), true
+	}
+}
+
+// asTime identifies time.Time values and RFC3339 timestamps.
+func asTime(value any) (time.Time, bool) {
+	switch typedValue := value.(type) {
+	case time.Time:
+		return typedValue, !typedValue.IsZero()
+	case *time.Time:
+		if typedValue == nil {
+			return time.Time{}, false
+		}
+
+		return *typedValue, !typedValue.IsZero()
+	case string:
+		timestamp, err := time.Parse(time.RFC3339, typedValue)
+
+		return timestamp, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+func asFloat(value any) (float64, bool) {
+	switch typedValue := value.(type) {
+	case json.Number:
+		float, err := typedValue.Float64()
+
+		return float, err == nil
+	case string:
+		float, err := strconv.ParseFloat(typedValue, 64)
+
+		return float, err == nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflectValue.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflectValue.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,131 @@
+package renderer_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type artifact struct {
+	Name     string        `json:"name"`
+	Size     int64         `json:"size" render:"size"`
+	Created  time.Time     `json:"created" render:"age"`
+	Duration time.Duration `json:"duration" render:"duration"`
+	Public   bool          `json:"public" render:"bool"`
+}
+
+func TestConfig_Formats(t *testing.T) {
+	created := time.Now().Add(-5*time.Minute - 10*time.Second)
+	artifacts := []artifact{
+		{Name: "build.tar", Size: 1288490189, Created: created, Duration: 1500 * time.Millisecond, Public: true},
+		{Name: "notes.txt", Size: 512, Created: created.Add(-50 * time.Hour), Duration: 90 * time.Minute},
+	}
+
+	t.Run("should format csv cells from struct tags", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		err := render.Render(artifacts)
+		require.NoError(t, err)
+		assert.Equal(t, "name,size,created,duration,public\n"+
+			"build.tar,1.2 GiB,5m ago,2s,✓\n"+
+			"notes.txt,512 B,2d ago,1h30m0s,✗\n", strReader.String())
+	})
+
+	t.Run("should format table cells selected by column", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, true)
+		render.Formats = map[string]string{"created": "time:UTC", "bytes": "size"}
+
+		err := render.Render([]map[string]any{{"bytes": 2048, "created": "2024-03-01T10:00:00+05:30"}})
+		require.NoError(t, err)
+		assert.Contains(t, strReader.String(), "2024-03-01T04:30:00Z")
+		assert.Contains(t, strReader.String(), "2.0 KiB")
+	})
+
+	t.Run("should format the values of key/value rows by key", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.Formats = map[string]string{"duration": "duration:1m"}
+
+		err := render.Render(artifacts[0])
+		require.NoError(t, err)
+		assert.Contains(t, strReader.String(), "size,1.2 GiB\n")
+		assert.Contains(t, strReader.String(), "duration,0s\n")
+	})
+
+	t.Run("should format numeric durations after query and in canonical form", func(t *testing.T) {
+		for _, configure := range []func(render *renderer.Config){
+			func(render *renderer.Config) { render.Query = ".[]" },
+			func(render *renderer.Config) { render.Canonical = true },
+		} {
+			strReader := new(bytes.Buffer)
+
+			render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+			render.Formats = map[string]string{"duration": "duration"}
+			render.CSVOptions = renderer.CSVOptions{Columns: []string{"name", "duration"}}
+			configure(&render)
+
+			err := render.Render(artifacts)
+			require.NoError(t, err)
+			assert.Equal(t, "name,duration\nbuild.tar,2s\nnotes.txt,1h30m0s\n", strReader.String())
+		}
+	})
+
+	t.Run("should format numbers of seconds as durations", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.Formats = map[string]string{"elapsed": "seconds", "timeout": "seconds:1m"}
+
+		err := render.Render([]map[string]any{{"elapsed": 90, "timeout": 3599.5}})
+		require.NoError(t, err)
+		assert.Equal(t, "elapsed,timeout\n1m30s,1h0m0s\n", strReader.String())
+	})
+
+	t.Run("should render values unsupported by the format as is", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.Formats = map[string]string{"size": "size"}
+
+		err := render.Render([]map[string]any{{"size": "unknown"}})
+		require.NoError(t, err)
+		assert.Equal(t, "size\nunknown\n", strReader.String())
+	})
+
+	t.Run("should keep json raw", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+
+		err := render.Render(artifacts[1])
+		require.NoError(t, err)
+		assert.Contains(t, strReader.String(), `"size": 512`)
+		assert.Contains(t, strReader.String(), `"public": false`)
+	})
+
+	t.Run("should error for unknown format", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, false, true, false)
+		render.Formats = map[string]string{"size": "bytes"}
+
+		err := render.Render(artifacts)
+		require.EqualError(t, err, "column 'size': unknown format 'bytes', should be one of 'age', 'size', 'duration', 'seconds', 'bool' or 'time'")
+	})
+
+	t.Run("should error for unknown time zone", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, false, true, false)
+		render.Formats = map[string]string{"created": "time:Mars/Olympus"}
+
+		err := render.Render(artifacts)
+		require.ErrorContains(t, err, "invalid time zone 'Mars/Olympus' for format 'time'")
+	})
+}
@@ -0,0 +1,210 @@
+package renderer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nikhilsbhat/common/content"
+	"github.com/nikhilsbhat/common/internal/terminal"
+)
+
+// hclBlockPattern matches the headers of HCL blocks, ex: 'resource "aws_instance" "web" {'.
+var hclBlockPattern = regexp.MustCompile(`^[A-Za-z_][\w-]*(\s+("[^"]*"|[A-Za-z_][\w-]*))*\s*\{$`)
+
+var csvColumnColors = []color.Attribute{
+	color.FgCyan,
+	color.FgGreen,
+	color.FgYellow,
+	color.FgMagenta,
+	color.FgBlue,
+}
+
+// Highlight adds colors to the content passed, the content type is identified with content.Object.CheckFileType.
+// Tables, HCL with blocks and shell scripts (starting with a shebang) are identified as well, any other content is returned as is.
+// HCL made of attributes alone is valid TOML and is colored as TOML.
+// Nothing is colored when Config.NoColor is enabled.
+func (cfg *Config) Highlight(text string) (string, error) {
+	if cfg.NoColor {
+		return text, nil
+	}
+
+	contentType := cfg.detectContentType(text)
+	if len(contentType) == 0 {
+		cfg.logger.Debug("could not identify the content type, skipping highlighting")
+
+		return text, nil
+	}
+
+	cfg.logger.Debugf("highlighting content identified as '%s'", contentType)
+
+	return cfg.Color(contentType, text)
+}
+
+// colored reports whether the output of the content type is colored, CSV and tables are colored only on terminals
+// since they are often read by other programs.
+func (cfg *Config) colored(contentType string) bool {
+	if cfg.NoColor {
+		return false
+	}
+
+	if contentType == TypeCSV || contentType == TypeTable {
+		return terminal.IsTerminal(cfg.output)
+	}
+
+	return true
+}
+
+func (cfg *Config) detectContentType(text string) string {
+	if isTable(text) {
+		return TypeTable
+	}
+
+	switch content.Object(text).CheckFileType(cfg.logger) {
+	case content.FileTypeJSON:
+		return TypeJSON
+	case content.FileTypeYAML:
+		// TOML starting with a table, ex: '[server]', parses as a YAML sequence as well.
+		if content.IsTOML(text) {
+			return TypeTOML
+		}
+
+		return TypeYAML
+	case content.FileTypeCSV:
+		return TypeCSV
+	case content.FileTypeXML:
+		return TypeXML
+	case content.FileTypeTOML:
+		return TypeTOML
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(text), "#!") {
+		return TypeShell
+	}
+
+	if isHCL(text) {
+		return TypeHCL
+	}
+
+	return ""
+}
+
+// isHCL identifies HCL with blocks, where the braces of the blocks are balanced and at least one line opens a block.
+func isHCL(text string) bool {
+	blocks, depth := 0, 0
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+
+		if hclBlockPattern.MatchString(line) {
+			blocks++
+		}
+
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth < 0 {
+			return false
+		}
+	}
+
+	return blocks != 0 && depth == 0
+}
+
+// isTable identifies the tables rendered by ToTable, where every line is either a border or a row.
+func isTable(text string) bool {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "+") {
+		return false
+	}
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "|") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// colorCSV colors every column of the CSV with alternating colors, quoted fields spanning multiple lines are honoured.
+func colorCSV(text string, delimiter rune) string {
+	var (
+		output   strings.Builder
+		field    strings.Builder
+		column   int
+		inQuotes bool
+	)
+
+	flushField := func() {
+		if field.Len() == 0 {
+			return
+		}
+
+		columnColor := color.New(csvColumnColors[column%len(csvColumnColors)])
+		columnColor.EnableColor()
+		output.WriteString(columnColor.Sprint(field.String()))
+		field.Reset()
+	}
+
+	for _, char := range text {
+		switch {
+		case char == '"':
+			inQuotes = !inQuotes
+
+			field.WriteRune(char)
+		case inQuotes:
+			field.WriteRune(char)
+		case char == delimiter:
+			flushField()
+			output.WriteRune(char)
+
+			column++
+		case char == '\n':
+			flushField()
+			output.WriteRune(char)
+
+			column = 0
+		default:
+			field.WriteRune(char)
+		}
+	}
+
+	flushField()
+
+	return output.String()
+}
+
+// colorTable emphasises the header of the table, which is its first row when header is set, and dims its borders.
+func colorTable(text string, header bool) string {
+	borderColor := color.New(color.Faint)
+	borderColor.EnableColor()
+
+	headerColor := color.New(color.Bold)
+	headerColor.EnableColor()
+
+	lines := strings.Split(text, "\n")
+	borders := 0
+
+	for index, line := range lines {
+		if strings.HasPrefix(line, "+") {
+			borders++
+			lines[index] = borderColor.Sprint(line)
+
+			continue
+		}
+
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+
+		cells := strings.Split(line, "|")
+		for cellIndex, cell := range cells {
+			if header && borders == 1 && len(strings.TrimSpace(cell)) != 0 {
+				cells[cellIndex] = headerColor.Sprint(cell)
+			}
+		}
+
+		lines[index] = strings.Join(cells, borderColor.Sprint("|"))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,128 @@
+package renderer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Highlight(t *testing.T) {
+	t.Run("should highlight json content after identifying its type", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+
+		out, err := config.Highlight(`{"name": "testing"}`)
+		require.NoError(t, err)
+		assert.Contains(t, out, "\x1b[")
+		assert.Contains(t, out, "testing")
+	})
+
+	t.Run("should highlight csv content by column", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+
+		out, err := config.Highlight("Name,Date\nnikhil,01-01-2024\njohn,01-02-2024")
+		require.NoError(t, err)
+		assert.Contains(t, out, "\x1b[36mName\x1b[0m,\x1b[32mDate\x1b[0m")
+		assert.Contains(t, out, "\x1b[36mnikhil\x1b[0m,\x1b[32m01-01-2024\x1b[0m")
+	})
+
+	t.Run("should keep quoted delimiters within the same csv column", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+
+		out, err := config.Color(renderer.TypeCSV, "Name,Place\n\"Booker, Rachel\",London")
+		require.NoError(t, err)
+		assert.Contains(t, out, "\x1b[36m\"Booker, Rachel\"\x1b[0m,\x1b[32mLondon\x1b[0m")
+	})
+
+	t.Run("should emphasise the header of the table", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+		render := renderer.GetRenderer(strReader, logrus.New(), false, false, false, false, true)
+
+		err := render.Render([][]string{{"sn", "cat"}, {"A", "The Good"}})
+		require.NoError(t, err)
+
+		out, err := render.Highlight(strReader.String())
+		require.NoError(t, err)
+		assert.Contains(t, out, "\x1b[1m sn \x1b[22m")
+		assert.NotContains(t, out, "\x1b[1m A")
+	})
+
+	t.Run("should highlight shell scripts identified by shebang", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+
+		out, err := config.Highlight("#!/bin/bash\necho \"hello\"")
+		require.NoError(t, err)
+		assert.Contains(t, out, "\x1b[")
+	})
+
+	t.Run("should highlight hcl with blocks after identifying its type", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+		hcl := "resource \"aws_instance\" \"web\" {\n  ami   = \"ami-123\"\n  count = 2\n\n  tags = {\n    Name = \"web\"\n  }\n}\n"
+
+		out, err := config.Highlight(hcl)
+		require.NoError(t, err)
+
+		expected, err := config.Color(renderer.TypeHCL, hcl)
+		require.NoError(t, err)
+		assert.Equal(t, expected, out)
+
+		out, err = config.Highlight("server {\n  port = 8080\n")
+		require.NoError(t, err)
+		assert.Equal(t, "server {\n  port = 8080\n", out)
+	})
+
+	t.Run("should highlight toml, xml and hcl when the type is specified", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+
+		for contentType, text := range map[string]string{
+			renderer.TypeTOML: "[server]\nport = 8080",
+			renderer.TypeXML:  "<server><port>8080</port></server>",
+			renderer.TypeHCL:  "server {\n  port = 8080\n}",
+		} {
+			out, err := config.Color(contentType, text)
+			require.NoError(t, err)
+			assert.Contains(t, out, "\x1b[", contentType)
+		}
+	})
+
+	t.Run("should highlight the output of ToXML and ToTOML after identifying its type", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+		value := map[string]any{"server": map[string]any{"port": 8080}}
+
+		for contentType, renderFunc := range map[string]func(render *renderer.Config) error{
+			renderer.TypeXML:  func(render *renderer.Config) error { return render.ToXML(value) },
+			renderer.TypeTOML: func(render *renderer.Config) error { return render.ToTOML(value) },
+		} {
+			strReader := new(bytes.Buffer)
+			render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, false)
+			require.NoError(t, renderFunc(&render))
+
+			out, err := config.Highlight(strReader.String())
+			require.NoError(t, err)
+
+			expected, err := config.Color(contentType, strReader.String())
+			require.NoError(t, err)
+			assert.Contains(t, out, "\x1b[", contentType)
+			assert.Equal(t, expected, out, contentType)
+		}
+	})
+
+	t.Run("should return the content as is when it could not be identified", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+
+		out, err := config.Highlight("just some text")
+		require.NoError(t, err)
+		assert.Equal(t, "just some text", out)
+	})
+
+	t.Run("should not color the content when no color is set", func(t *testing.T) {
+		config := renderer.GetRenderer(nil, logrus.New(), true, false, false, false, false)
+
+		out, err := config.Highlight(`{"name": "testing"}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "testing"}`, out)
+	})
+}
@@ -0,0 +1,453 @@
+package renderer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// defaultTableBatchSize is the number of rows rendered at a time by streamTable when Config.TableBatchSize is not set.
+const defaultTableBatchSize = 100
+
+// sequence wraps the iter.Seq and iter.Seq2 values passed to the renderer, of any element type.
+type sequence struct {
+	value reflect.Value
+	pairs bool
+}
+
+// asSequence identifies iter.Seq[T] and iter.Seq2[K, V] values, which are functions of form func(yield func(T) bool) and func(yield func(K, V) bool).
+func asSequence(value any) (sequence, bool) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.Func || reflectValue.IsNil() {
+		return sequence{}, false
+	}
+
+	funcType := reflectValue.Type()
+	if funcType.NumIn() != 1 || funcType.NumOut() != 0 {
+		return sequence{}, false
+	}
+
+	yieldType := funcType.In(0)
+	if yieldType.Kind() != reflect.Func || yieldType.NumOut() != 1 || yieldType.Out(0).Kind() != reflect.Bool {
+		return sequence{}, false
+	}
+
+	switch yieldType.NumIn() {
+	case 1:
+		return sequence{value: reflectValue}, true
+	case 2: //nolint:mnd
+		return sequence{value: reflectValue, pairs: true}, true
+	default:
+		return sequence{}, false
+	}
+}
+
+// each calls the function for every element of the sequence until it returns false, key is always nil for iter.Seq.
+func (seq sequence) each(function func(key, value any) bool) {
+	yield := reflect.MakeFunc(seq.value.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		var key, value any
+
+		if seq.pairs {
+			key, value = args[0].Interface(), args[1].Interface()
+		} else {
+			value = args[0].Interface()
+		}
+
+		return []reflect.Value{reflect.ValueOf(function(key, value))}
+	})
+
+	seq.value.Call([]reflect.Value{yield})
+}
+
+// collect materializes the sequence, iter.Seq as a slice and iter.Seq2 as a map with the keys formatted as strings.
+func (seq sequence) collect() any {
+	if seq.pairs {
+		values := make(map[string]any)
+
+		seq.each(func(key, value any) bool {
+			values[fmt.Sprint(key)] = value
+
+			return true
+		})
+
+		return values
+	}
+
+	values := make([]any, 0)
+
+	seq.each(func(_, value any) bool {
+		values = append(values, value)
+
+		return true
+	})
+
+	return values
+}
+
+// replayable collects the elements of the sequence once, returning a sequence of the same type that yields them every time it is ranged over.
+func (seq sequence) replayable() any {
+	elements := make([][]reflect.Value, 0)
+
+	yield := reflect.MakeFunc(seq.value.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		elements = append(elements, append([]reflect.Value(nil), args...))
+
+		return []reflect.Value{reflect.ValueOf(true)}
+	})
+
+	seq.value.Call([]reflect.Value{yield})
+
+	return reflect.MakeFunc(seq.value.Type(), func(args []reflect.Value) []reflect.Value {
+		for _, element := range elements {
+			if !args[0].Call(element)[0].Bool() {
+				break
+			}
+		}
+
+		return nil
+	}).Interface()
+}
+
+// materialize collects the value if it is a sequence, for the formats that cannot be rendered lazily.
+func materialize(value any) any {
+	if seq, ok := asSequence(value); ok {
+		return seq.collect()
+	}
+
+	return value
+}
+
+// streamJSON renders the sequence lazily as a JSON array, or as a JSON object for iter.Seq2, encoding an element at a time.
+// Every element is encoded wrapped in its own array (or object) and unwrapped, so that the indentation matches the materialized value.
+func (cfg *Config) streamJSON(seq sequence) error {
+	cfg.logger.Debug("rendering sequence lazily in json format")
+
+	open, closing := "[", "]"
+	if seq.pairs {
+		open, closing = "{", "}"
+	}
+
+	newline := "\n"
+	if cfg.Encoding.Compact {
+		newline = ""
+	}
+
+	count := 0
+
+	err := cfg.stream(seq, TypeJSON, func(key, value any) (string, error) {
+		element := any([]any{value})
+		if seq.pairs {
+			element = map[string]any{fmt.Sprint(key): value}
+		}
+
+		elementJSON, err := cfg.Encoding.EncodeJSON(element)
+		if err != nil {
+			return "", err
+		}
+
+		chunk := strings.TrimSuffix(strings.TrimPrefix(string(elementJSON), open+newline), newline+closing)
+
+		prefix := open + newline
+		if count != 0 {
+			prefix = "," + newline
+		}
+
+		count++
+
+		return prefix + chunk, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tail := newline + closing
+	if count == 0 {
+		tail = open + closing
+	}
+
+	if err = cfg.writeChunk(tail, TypeJSON); err != nil {
+		return err
+	}
+
+	return cfg.writer.Flush()
+}
+
+// streamYAML renders the sequence lazily as a YAML sequence, or as a YAML mapping for iter.Seq2, encoding an element at a time.
+func (cfg *Config) streamYAML(seq sequence) error {
+	cfg.logger.Debug("rendering sequence lazily in yaml format")
+
+	elementEncoding := cfg.Encoding
+	elementEncoding.NoDocumentSeparator = true
+
+	count := 0
+
+	err := cfg.stream(seq, TypeYAML, func(key, value any) (string, error) {
+		element := any([]any{value})
+		if seq.pairs {
+			element = yaml.MapSlice{{Key: fmt.Sprint(key), Value: value}}
+		}
+
+		valueYAML, err := elementEncoding.EncodeYAML(element)
+		if err != nil {
+			return "", err
+		}
+
+		prefix := ""
+		if count == 0 && !cfg.Encoding.NoDocumentSeparator {
+			prefix = "---\n"
+		}
+
+		count++
+
+		return prefix + string(valueYAML), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return cfg.writer.Flush()
+	}
+
+	var empty any = []any{}
+	if seq.pairs {
+		empty = map[string]any{}
+	}
+
+	valueYAML, err := cfg.Encoding.EncodeYAML(empty)
+	if err != nil {
+		return err
+	}
+
+	if err = cfg.writeChunk(string(valueYAML), TypeYAML); err != nil {
+		return err
+	}
+
+	return cfg.writer.Flush()
+}
+
+// streamCSV renders the sequence lazily as CSV rows, the columns are identified from the first element.
+// iter.Seq2 is rendered as key/value rows.
+func (cfg *Config) streamCSV(seq sequence) error {
+	cfg.logger.Debug("rendering sequence lazily in csv format")
+
+	opts := cfg.CSVOptions
+
+	delimiter, err := opts.delimiter()
+	if err != nil {
+		return err
+	}
+
+	var headers []string
+
+	return cfg.stream(seq, TypeCSV, func(key, value any) (string, error) {
+		row := value
+		if seq.pairs {
+			row = map[string]any{keyColumn: key, valueColumn: value}
+		}
+
+		rec := tabulate([]any{row}, opts.Flatten, false)
+		if seq.pairs {
+			rec.headers = []string{keyColumn, valueColumn}
+		}
+
+		if err := cfg.formatRecords(rec); err != nil {
+			return "", err
+		}
+
+		lines := make([][]string, 0)
+
+		if headers == nil {
+			rec.selectColumns(opts.Columns)
+			headers = rec.headers
+
+			if !opts.NoHeader {
+				lines = append(lines, headers)
+			}
+		}
+
+		rec.headers = headers
+		lines = append(lines, rec.cells(rec.rows[0], opts.sliceSeparator()))
+
+		return writeCSV(lines, delimiter, opts.Quote)
+	})
+}
+
+// streamTable renders the sequence lazily as table rows, Config.TableBatchSize rows at a time, iter.Seq2 is rendered as key/value rows.
+// tablewriter sizes the columns from every row of the table, so every batch is rendered as a table of its own continuing the previous one:
+// the columns are identified from the first batch and kept at least as wide as in the previous batches, widening from a batch with wider cells.
+func (cfg *Config) streamTable(seq sequence) error {
+	cfg.logger.Debug("rendering sequence lazily in table format")
+
+	colorRules, err := cfg.colorRules()
+	if err != nil {
+		return err
+	}
+
+	batchSize := cfg.TableBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultTableBatchSize
+	}
+
+	var (
+		headers  []string
+		widths   []int
+		rendered bool
+	)
+
+	rows := make([]any, 0, batchSize)
+
+	flush := func() error {
+		rec := tabulate(rows, !seq.pairs, false)
+
+		switch {
+		case headers != nil:
+			rec.headers = headers
+		case seq.pairs:
+			rec.headers = []string{keyColumn, valueColumn}
+		}
+
+		headers = rec.headers
+
+		if err := cfg.formatRecords(rec); err != nil {
+			return err
+		}
+
+		tableString := &strings.Builder{}
+		table := newTable(tableString)
+
+		for column, width := range widths {
+			table.SetColMinWidth(column, width)
+		}
+
+		appendRecords(table, rec, colorRules)
+		table.Render()
+
+		chunk := tableString.String()
+		widths = tableWidths(chunk)
+
+		if rendered {
+			chunk = tableRows(chunk)
+		}
+
+		if cfg.colored(TypeTable) {
+			chunk = colorTable(chunk, !rendered)
+		}
+
+		rendered = true
+		rows = rows[:0]
+
+		if err := cfg.writeChunk(chunk, TypeTable); err != nil {
+			return err
+		}
+
+		return cfg.writer.Flush()
+	}
+
+	seq.each(func(key, value any) bool {
+		row := value
+		if seq.pairs {
+			row = map[string]any{keyColumn: key, valueColumn: value}
+		}
+
+		if rows = append(rows, row); len(rows) < batchSize {
+			return true
+		}
+
+		err = flush()
+
+		return err == nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(rows) != 0 || !rendered {
+		return flush()
+	}
+
+	return nil
+}
+
+// tableWidths returns the widths of the columns of the table rendered by tablewriter, identified from its top border.
+func tableWidths(table string) []int {
+	border, _, _ := strings.Cut(table, "\n")
+	segments := strings.Split(strings.Trim(border, "+"), "+")
+
+	widths := make([]int, 0, len(segments))
+	for _, segment := range segments {
+		// every cell is padded with a space on both sides.
+		widths = append(widths, max(len(segment)-2, 0)) //nolint:mnd
+	}
+
+	return widths
+}
+
+// tableRows drops the top border and the header of the table rendered by tablewriter, continuing the table rendered before it.
+func tableRows(table string) string {
+	lines := strings.SplitAfter(table, "\n")
+	borders := 0
+
+	for index, line := range lines {
+		if !strings.HasPrefix(line, "+") {
+			continue
+		}
+
+		if borders++; borders == 2 { //nolint:mnd
+			return strings.Join(lines[index+1:], "")
+		}
+	}
+
+	return table
+}
+
+// stream writes and flushes the chunks returned by encode for every element of the sequence, coloring them when enabled.
+func (cfg *Config) stream(seq sequence, contentType string, encode func(key, value any) (string, error)) error {
+	var err error
+
+	seq.each(func(key, value any) bool {
+		var chunk string
+
+		if chunk, err = encode(key, value); err != nil {
+			return false
+		}
+
+		if err = cfg.writeChunk(chunk, contentType); err != nil {
+			return false
+		}
+
+		err = cfg.writer.Flush()
+
+		return err == nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return cfg.writer.Flush()
+}
+
+func (cfg *Config) writeChunk(chunk, contentType string) error {
+	if err := cfg.stopProgress(); err != nil {
+		return err
+	}
+
+	// the tables are colored by streamTable, which knows whether the chunk starts with the header.
+	if contentType != TypeTable && cfg.colored(contentType) {
+		coloredChunk, err := cfg.Color(contentType, chunk)
+		if err != nil {
+			return err
+		}
+
+		chunk = coloredChunk
+	}
+
+	if _, err := cfg.writer.WriteString(chunk); err != nil {
+		return err
+	}
+
+	return nil
+}
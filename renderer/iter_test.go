@@ -0,0 +1,167 @@
+package renderer_test
+
+import (
+	"bytes"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/nikhilsbhat/common/content"
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pipelineSeq() iter.Seq[pipelineStatus] {
+	return slices.Values(pipelineStatuses["pipelines"].([]pipelineStatus))
+}
+
+func TestConfig_RenderSequence(t *testing.T) {
+	pipelines := pipelineStatuses["pipelines"].([]pipelineStatus)
+
+	for _, format := range []string{renderer.TypeJSON, renderer.TypeYAML, renderer.TypeTable} {
+		t.Run("should render iter.Seq same as slice in "+format, func(t *testing.T) {
+			expected, actual := new(bytes.Buffer), new(bytes.Buffer)
+
+			render := renderer.GetRenderer(expected, logrus.New(), true, false, false, false, false)
+			require.NoError(t, render.SetFormat(format))
+			require.NoError(t, render.Render(pipelines))
+
+			render = renderer.GetRenderer(actual, logrus.New(), true, false, false, false, false)
+			require.NoError(t, render.SetFormat(format))
+			require.NoError(t, render.Render(pipelineSeq()))
+
+			assert.Equal(t, expected.String(), actual.String())
+		})
+	}
+
+	t.Run("should render iter.Seq as csv rows", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		err := render.Render(pipelineSeq())
+		require.NoError(t, err)
+		assert.Equal(t, "name,status,counter,stages\nbuild,Passed,10,compile|test\ndeploy,Failed,3,deploy\nrelease,Building,7,\n", strReader.String())
+	})
+
+	t.Run("should render iter.Seq with compact json", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		render.Encoding = content.EncodeOptions{Compact: true}
+
+		err := render.Render(slices.Values([]int{1, 2, 3}))
+		require.NoError(t, err)
+		assert.Equal(t, "[1,2,3]", strReader.String())
+	})
+
+	t.Run("should render empty iter.Seq", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+
+		err := render.Render(slices.Values([]string{}))
+		require.NoError(t, err)
+		assert.Equal(t, "[]", strReader.String())
+	})
+
+	t.Run("should render iter.Seq2 in order", func(t *testing.T) {
+		keys := []string{"zeta", "alpha", "mid"}
+		seq := func(yield func(string, int) bool) {
+			for index, key := range keys {
+				if !yield(key, index) {
+					return
+				}
+			}
+		}
+
+		strReader := new(bytes.Buffer)
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+
+		require.NoError(t, render.Render(iter.Seq2[string, int](seq)))
+		assert.Equal(t, "{\n     \"zeta\": 0,\n     \"alpha\": 1,\n     \"mid\": 2\n}", strReader.String())
+
+		strReader.Reset()
+		require.NoError(t, render.SetFormat(renderer.TypeYAML))
+		require.NoError(t, render.Render(iter.Seq2[string, int](seq)))
+		assert.Equal(t, "---\nzeta: 0\nalpha: 1\nmid: 2\n", strReader.String())
+
+		strReader.Reset()
+		require.NoError(t, render.SetFormat(renderer.TypeCSV))
+		require.NoError(t, render.Render(iter.Seq2[string, int](seq)))
+		assert.Equal(t, "key,value\nzeta,0\nalpha,1\nmid,2\n", strReader.String())
+	})
+
+	t.Run("should consume the sequence lazily", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+
+		seq := func(yield func(map[string]any) bool) {
+			for index := range 3 {
+				before := strReader.Len()
+				if !yield(map[string]any{"index": index}) {
+					return
+				}
+
+				assert.Greater(t, strReader.Len(), before)
+			}
+		}
+
+		render.CSVOptions = renderer.CSVOptions{Delimiter: renderer.CSVDelimiterTab}
+		require.NoError(t, render.Render(iter.Seq[map[string]any](seq)))
+		assert.Equal(t, "index\n0\n1\n2\n", strReader.String())
+	})
+
+	t.Run("should render the table in batches continuing the previous batch", func(t *testing.T) {
+		rows := []map[string]any{{"name": "release", "counter": 100}, {"name": "build", "counter": 1}, {"name": "test", "counter": 2}}
+		expected, actual := new(bytes.Buffer), new(bytes.Buffer)
+
+		render := renderer.GetRenderer(expected, logrus.New(), true, false, false, false, true)
+		require.NoError(t, render.Render(rows))
+
+		render = renderer.GetRenderer(actual, logrus.New(), true, false, false, false, true)
+		render.TableBatchSize = 2
+
+		seq := func(yield func(map[string]any) bool) {
+			for index, row := range rows {
+				before := actual.Len()
+				if !yield(row) {
+					return
+				}
+
+				if index == 1 {
+					assert.Greater(t, actual.Len(), before)
+				}
+			}
+		}
+
+		require.NoError(t, render.Render(iter.Seq[map[string]any](seq)))
+		assert.Equal(t, expected.String(), actual.String())
+	})
+
+	t.Run("should render iter.Seq2 as key/value table rows", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, true)
+
+		require.NoError(t, render.Render(maps.All(map[string]int{"build": 10})))
+		assert.Equal(t, "+-------+-------+\n|  KEY  | VALUE |\n+-------+-------+\n| build |  10   |\n+-------+-------+\n", strReader.String())
+	})
+
+	t.Run("should collect the sequence before evaluating query", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		render.Query = "map(.name)"
+		render.QueryUnwrap = true
+
+		err := render.Render(maps.Keys(map[string]bool{"build": true}))
+		require.Error(t, err)
+
+		strReader.Reset()
+		require.NoError(t, render.Render(pipelineSeq()))
+		assert.JSONEq(t, `["build","deploy","release"]`, strReader.String())
+	})
+}
@@ -0,0 +1,109 @@
+package renderer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Target is one of the destinations of MultiRenderer.
+type Target struct {
+	// Writer to render to, defaults to os.Stdout.
+	Writer io.Writer
+	// Format is one of TypeYAML, TypeJSON, TypeCSV, TypeTable, TypeXML, TypeTOML or TypeTree, the value is rendered as is when empty.
+	Format string
+	// NoColor disables the colors for this target.
+	NoColor bool
+}
+
+// TargetError is the error rendering the value to one of the targets of MultiRenderer.
+type TargetError struct {
+	Index  int
+	Format string
+	Err    error
+}
+
+// Error implements error.
+func (e *TargetError) Error() string {
+	return fmt.Sprintf("rendering to target %d in format '%s' errored with '%v'", e.Index, e.Format, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TargetError) Unwrap() error {
+	return e.Err
+}
+
+// MultiRenderError holds the errors of every target that could not be rendered.
+type MultiRenderError struct {
+	Errors []*TargetError
+}
+
+// Error implements error.
+func (e *MultiRenderError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, targetError := range e.Errors {
+		messages = append(messages, targetError.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// MultiRenderer renders a single value to several targets, each with its own format and color mode.
+// Every other option such as Query, Canonical or Encoding is taken from the Config it was created from.
+type MultiRenderer struct {
+	Targets []Target
+	config  Config
+}
+
+// NewMultiRenderer returns a new instance of MultiRenderer that renders to the targets with the options of config.
+func NewMultiRenderer(config Config, targets ...Target) *MultiRenderer {
+	return &MultiRenderer{
+		Targets: targets,
+		config:  config,
+	}
+}
+
+// Render renders the value to all the targets.
+// A failing target does not stop the others from being rendered, the errors of all failed targets are returned as MultiRenderError.
+// iter.Seq and iter.Seq2 values are ranged over once, their elements are collected to be rendered to every target.
+func (multi *MultiRenderer) Render(value any) error {
+	var targetErrors []*TargetError
+
+	if seq, ok := asSequence(value); ok && len(multi.Targets) > 1 {
+		value = seq.replayable()
+	}
+
+	for index, target := range multi.Targets {
+		multi.config.logger.Debugf("rendering output to target %d in format '%s'", index, target.Format)
+
+		if err := multi.render(target, value); err != nil {
+			targetErrors = append(targetErrors, &TargetError{Index: index, Format: target.Format, Err: err})
+		}
+	}
+
+	if len(targetErrors) != 0 {
+		return &MultiRenderError{Errors: targetErrors}
+	}
+
+	return nil
+}
+
+func (multi *MultiRenderer) render(target Target, value any) error {
+	config := multi.config
+	config.NoColor = target.NoColor
+
+	config.output = target.Writer
+	if config.output == nil {
+		config.output = os.Stdout
+	}
+
+	config.writer = bufio.NewWriter(config.output)
+
+	if err := config.SetFormat(target.Format); err != nil {
+		return err
+	}
+
+	return config.Render(value)
+}
@@ -0,0 +1,109 @@
+package renderer_test
+
+import (
+	"bytes"
+	stdErrors "errors"
+	"iter"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, stdErrors.New("disk full")
+}
+
+func TestMultiRenderer_Render(t *testing.T) {
+	value := []map[string]any{{"name": "build", "status": "Passed"}}
+
+	t.Run("should render the value to all targets in their formats", func(t *testing.T) {
+		table, report := new(bytes.Buffer), new(bytes.Buffer)
+
+		base := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+		multi := renderer.NewMultiRenderer(base,
+			renderer.Target{Writer: table, Format: renderer.TypeTable, NoColor: true},
+			renderer.Target{Writer: report, Format: renderer.TypeJSON, NoColor: true},
+		)
+
+		err := multi.Render(value)
+		require.NoError(t, err)
+		assert.Contains(t, table.String(), "| build |")
+		assert.JSONEq(t, `[{"name":"build","status":"Passed"}]`, report.String())
+	})
+
+	t.Run("should apply the options of the base config to all targets", func(t *testing.T) {
+		yamlOut, csvOut := new(bytes.Buffer), new(bytes.Buffer)
+
+		base := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+		base.Query = "map(.name)"
+		base.QueryUnwrap = true
+
+		multi := renderer.NewMultiRenderer(base,
+			renderer.Target{Writer: yamlOut, Format: renderer.TypeYAML, NoColor: true},
+			renderer.Target{Writer: csvOut, Format: renderer.TypeCSV, NoColor: true},
+		)
+
+		err := multi.Render(value)
+		require.NoError(t, err)
+		assert.Equal(t, "---\n  - build\n", yamlOut.String())
+		assert.Equal(t, "value\nbuild\n", csvOut.String())
+	})
+
+	t.Run("should render the sequences that could be ranged over once to all targets", func(t *testing.T) {
+		yamlOut, jsonOut := new(bytes.Buffer), new(bytes.Buffer)
+
+		consumed := false
+		names := func(yield func(string) bool) {
+			if consumed {
+				return
+			}
+
+			consumed = true
+
+			for _, name := range []string{"build", "deploy"} {
+				if !yield(name) {
+					return
+				}
+			}
+		}
+
+		base := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+		multi := renderer.NewMultiRenderer(base,
+			renderer.Target{Writer: yamlOut, Format: renderer.TypeYAML, NoColor: true},
+			renderer.Target{Writer: jsonOut, Format: renderer.TypeJSON, NoColor: true},
+		)
+
+		err := multi.Render(iter.Seq[string](names))
+		require.NoError(t, err)
+		assert.Equal(t, "---\n  - build\n  - deploy\n", yamlOut.String())
+		assert.JSONEq(t, `["build","deploy"]`, jsonOut.String())
+	})
+
+	t.Run("should report errors per target without aborting the others", func(t *testing.T) {
+		report := new(bytes.Buffer)
+
+		base := renderer.GetRenderer(nil, logrus.New(), false, false, false, false, false)
+		multi := renderer.NewMultiRenderer(base,
+			renderer.Target{Writer: failingWriter{}, Format: renderer.TypeYAML},
+			renderer.Target{Writer: new(bytes.Buffer), Format: "ini"},
+			renderer.Target{Writer: report, Format: renderer.TypeJSON, NoColor: true},
+		)
+
+		err := multi.Render(value)
+		require.Error(t, err)
+		assert.JSONEq(t, `[{"name":"build","status":"Passed"}]`, report.String())
+
+		var multiErr *renderer.MultiRenderError
+		require.ErrorAs(t, err, &multiErr)
+		require.Len(t, multiErr.Errors, 2)
+		assert.Equal(t, 0, multiErr.Errors[0].Index)
+		assert.EqualError(t, multiErr.Errors[0].Err, "disk full")
+		assert.Equal(t, 1, multiErr.Errors[1].Index)
+		assert.EqualError(t, multiErr.Errors[1], "rendering to target 1 in format 'ini' errored with 'unknown format 'ini', cannot render the value'")
+	})
+}
@@ -0,0 +1,260 @@
+package renderer
+
+import (
+	"bufio"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/nikhilsbhat/common/internal/terminal"
+)
+
+const (
+	// PagerEnv is the environment variable holding the pager command used when Config.Pager is enabled.
+	PagerEnv = "PAGER"
+	// NoPagerEnv disables the pager regardless of Config.Pager when set to a non-empty value.
+	NoPagerEnv = "NO_PAGER"
+	// DefaultPager is the pager used when PagerEnv is not set.
+	DefaultPager = "less -R"
+)
+
+// FallbackPager is a minimal built-in pager used when the configured pager is not available.
+// It prints the output a page at a time, waiting for enter to continue or q to quit.
+type FallbackPager struct {
+	writer io.Writer
+	input  io.Reader
+	height int
+}
+
+// NewFallbackPager returns a new instance of FallbackPager that pages to writer, reading the keys from input.
+func NewFallbackPager(writer io.Writer, input io.Reader, height int) *FallbackPager {
+	return &FallbackPager{
+		writer: writer,
+		input:  input,
+		height: height,
+	}
+}
+
+// Page prints the text a page at a time.
+func (pager *FallbackPager) Page(text string) error {
+	lines := strings.SplitAfter(text, "\n")
+	reader := bufio.NewReader(pager.input)
+
+	// one line of the terminal is reserved for the prompt.
+	pageSize := max(pager.height-1, 1)
+
+	for start := 0; start < len(lines); start += pageSize {
+		end := min(start+pageSize, len(lines))
+
+		if _, err := io.WriteString(pager.writer, strings.Join(lines[start:end], "")); err != nil {
+			return err
+		}
+
+		if end == len(lines) {
+			return nil
+		}
+
+		if _, err := io.WriteString(pager.writer, "-- More -- (enter to continue, q to quit)"); err != nil {
+			return err
+		}
+
+		key, err := reader.ReadString('\n')
+
+		if _, clearErr := io.WriteString(pager.writer, "\r\x1b[K"); clearErr != nil {
+			return clearErr
+		}
+
+		if err != nil || strings.TrimSpace(key) == "q" {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// pagerHeight returns the height of the terminal if the output has to be paged.
+// Output is paged only when Config.Pager is enabled, the writer is a terminal and the output does not fit in it.
+func (cfg *Config) pagerHeight(out string) (int, bool) {
+	height, ok := cfg.terminalHeight()
+
+	return height, ok && strings.Count(out, "\n") >= height
+}
+
+// terminalHeight returns the height of the terminal when Config.Pager is enabled and the writer is a terminal.
+func (cfg *Config) terminalHeight() (int, bool) {
+	if !cfg.Pager || len(os.Getenv(NoPagerEnv)) != 0 {
+		return 0, false
+	}
+
+	height, ok, err := terminal.Height(cfg.output)
+	if !ok {
+		return 0, false
+	}
+
+	if err != nil {
+		cfg.logger.Debugf("could not identify the terminal size, skipping pager: %v", err)
+
+		return 0, false
+	}
+
+	return height, true
+}
+
+// page pipes the output through the pager set in PagerEnv, falling back to FallbackPager when it could not be found.
+func (cfg *Config) page(out string, height int) error {
+	if err := cfg.writer.Flush(); err != nil {
+		return err
+	}
+
+	cmd, ok := cfg.pagerCommand()
+	if !ok {
+		return NewFallbackPager(cfg.output, cfg.input, height).Page(out)
+	}
+
+	cmd.Stdin = strings.NewReader(out)
+
+	if err := cmd.Run(); err != nil {
+		return &errors.CommonError{Message: fmt.Sprintf("running pager '%s' errored with '%v'", cmd.Path, err)}
+	}
+
+	return nil
+}
+
+// pagerCommand returns the command of the pager set in PagerEnv writing to the output, false when it could not be found.
+func (cfg *Config) pagerCommand() (*exec.Cmd, bool) {
+	pagerCommand := strings.Fields(os.Getenv(PagerEnv))
+	if len(pagerCommand) == 0 {
+		pagerCommand = strings.Fields(DefaultPager)
+	}
+
+	pagerPath, err := exec.LookPath(pagerCommand[0])
+	if err != nil {
+		cfg.logger.Debugf("pager '%s' not found, falling back to built-in pager", pagerCommand[0])
+
+		return nil, false
+	}
+
+	cfg.logger.Debugf("paging output with '%s'", strings.Join(pagerCommand, " "))
+
+	cmd := exec.Command(pagerPath, pagerCommand[1:]...) //nolint:gosec
+	cmd.Stdout = cfg.output
+	cmd.Stderr = os.Stderr
+
+	return cmd, true
+}
+
+// streamPaged renders the sequence with render, through streamPager when Config.Pager is enabled and the writer is a terminal.
+func (cfg *Config) streamPaged(seq sequence, render func(seq sequence) error) error {
+	height, ok := cfg.terminalHeight()
+	if !ok {
+		return render(seq)
+	}
+
+	if err := cfg.stopProgress(); err != nil {
+		return err
+	}
+
+	pager := &streamPager{config: cfg, height: height}
+
+	writer := cfg.writer
+	cfg.writer = bufio.NewWriter(pager)
+
+	defer func() {
+		cfg.writer = writer
+	}()
+
+	err := render(seq)
+	if stdErrors.Is(err, errPagerClosed) {
+		cfg.logger.Debug("pager exited before the sequence was rendered completely")
+
+		err = nil
+	}
+
+	if closeErr := pager.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// errPagerClosed stops rendering the sequence once the pager has exited, ex: on quitting less.
+var errPagerClosed = stdErrors.New("pager closed")
+
+// streamPager pages the output of the sequences as it is rendered. The output is held until it fills the terminal, from then on
+// it is piped through the pager set in PagerEnv as it is written, or collected for FallbackPager when it could not be found.
+// Output that never fills the terminal is written as is once the sequence ends.
+type streamPager struct {
+	config   *Config
+	height   int
+	buffer   strings.Builder
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	fallback bool
+}
+
+// Write implements io.Writer.
+func (pager *streamPager) Write(data []byte) (int, error) {
+	if pager.stdin != nil {
+		if _, err := pager.stdin.Write(data); err != nil {
+			return 0, errPagerClosed
+		}
+
+		return len(data), nil
+	}
+
+	pager.buffer.Write(data)
+
+	if pager.fallback || strings.Count(pager.buffer.String(), "\n") < pager.height {
+		return len(data), nil
+	}
+
+	cmd, ok := pager.config.pagerCommand()
+	if !ok {
+		pager.fallback = true
+
+		return len(data), nil
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return 0, err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return 0, &errors.CommonError{Message: fmt.Sprintf("running pager '%s' errored with '%v'", cmd.Path, err)}
+	}
+
+	pager.cmd, pager.stdin = cmd, stdin
+
+	if _, err = io.WriteString(stdin, pager.buffer.String()); err != nil {
+		return 0, errPagerClosed
+	}
+
+	pager.buffer.Reset()
+
+	return len(data), nil
+}
+
+// Close writes the output that fits the terminal as is, pages it with FallbackPager or waits for the pager to exit.
+func (pager *streamPager) Close() error {
+	switch {
+	case pager.stdin != nil:
+		_ = pager.stdin.Close()
+
+		if err := pager.cmd.Wait(); err != nil {
+			return &errors.CommonError{Message: fmt.Sprintf("running pager '%s' errored with '%v'", pager.cmd.Path, err)}
+		}
+
+		return nil
+	case pager.fallback:
+		return NewFallbackPager(pager.config.output, pager.config.input, pager.height).Page(pager.buffer.String())
+	default:
+		_, err := io.WriteString(pager.config.output, pager.buffer.String())
+
+		return err
+	}
+}
@@ -0,0 +1,69 @@
+package renderer_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackPager_Page(t *testing.T) {
+	text := "line1\nline2\nline3\nline4\nline5\n"
+
+	t.Run("should print the output a page at a time", func(t *testing.T) {
+		out := new(bytes.Buffer)
+
+		err := renderer.NewFallbackPager(out, strings.NewReader("\n\n"), 3).Page(text)
+		require.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(out.String(), "-- More --"))
+		assert.Contains(t, out.String(), "line5")
+	})
+
+	t.Run("should stop paging once quit", func(t *testing.T) {
+		out := new(bytes.Buffer)
+
+		err := renderer.NewFallbackPager(out, strings.NewReader("q\n"), 3).Page(text)
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "line2")
+		assert.NotContains(t, out.String(), "line3")
+	})
+
+	t.Run("should print everything when the output fits in a page", func(t *testing.T) {
+		out := new(bytes.Buffer)
+
+		err := renderer.NewFallbackPager(out, strings.NewReader(""), 10).Page(text)
+		require.NoError(t, err)
+		assert.Equal(t, text, out.String())
+	})
+}
+
+func TestConfig_Pager(t *testing.T) {
+	t.Run("should not page the output when the writer is not a terminal", func(t *testing.T) {
+		t.Setenv(renderer.PagerEnv, "false")
+
+		strReader := new(bytes.Buffer)
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		render.Pager = true
+
+		err := render.Render(map[string]string{"name": "testing"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "testing"}`, strReader.String())
+	})
+
+	t.Run("should stream the sequences as is when the writer is not a terminal", func(t *testing.T) {
+		t.Setenv(renderer.PagerEnv, "false")
+
+		strReader := new(bytes.Buffer)
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.Pager = true
+
+		err := render.Render(slices.Values([]string{"build", "deploy"}))
+		require.NoError(t, err)
+		assert.Equal(t, "value\nbuild\ndeploy\n", strReader.String())
+	})
+}
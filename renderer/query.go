@@ -0,0 +1,1178 @@
+package renderer
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nikhilsbhat/common/errors"
+)
+
+// Query is a compiled query expression, supporting a subset of jq that works for both jq and yq style queries.
+//
+// Supported expressions are:
+//
+//	.                          identity
+//	.name, .["name"], .a.b     field selection
+//	.[0], .[-1], .[1:3]        index and slice selection
+//	.[]                        iterating over arrays and objects
+//	..                         recursing over every value
+//	a | b                      pipes
+//	a, b                       multiple outputs
+//	[a], {name: a}             array and object construction
+//	==, !=, <, <=, >, >=       comparisons
+//	and, or, not               boolean logic
+//	map(f), select(f)          mapping and filtering
+//	keys, length, has(key)     builtins
+//
+// Queries are evaluated against the JSON representation of the value, which is what jq would have seen on '-o json'.
+// The numbers are kept as written there, so that the integers are output as integers without losing precision.
+type Query struct {
+	expression string
+	root       queryNode
+}
+
+type queryNode func(input any) ([]any, error)
+
+// ParseQuery compiles the query expression passed.
+func ParseQuery(expression string) (*Query, error) {
+	tokens, err := tokenizeQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &queryParser{tokens: tokens, expression: expression}
+
+	root, err := parser.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if !parser.done() {
+		return nil, parser.errorf("unexpected '%s'", parser.peek().value)
+	}
+
+	return &Query{expression: expression, root: root}, nil
+}
+
+// Evaluate evaluates the query against the value, returning every output of the query.
+func (query *Query) Evaluate(value any) ([]any, error) {
+	input, err := toGeneric(value)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := query.root(input)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, output := range outputs {
+		outputs[index] = canonicalValue(output, "")
+	}
+
+	return outputs, nil
+}
+
+// String returns the expression the query was compiled from.
+func (query *Query) String() string {
+	return query.expression
+}
+
+// applyQuery evaluates Config.Query against the value, returning the list of outputs or the only output when Config.QueryUnwrap is set.
+func (cfg *Config) applyQuery(value any) (any, error) {
+	if len(strings.TrimSpace(cfg.Query)) == 0 {
+		return value, nil
+	}
+
+	cfg.logger.Debugf("evaluating query '%s' before rendering", cfg.Query)
+
+	query, err := ParseQuery(cfg.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := query.Evaluate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.QueryUnwrap {
+		return outputs, nil
+	}
+
+	if len(outputs) != 1 {
+		return nil, &errors.CommonError{
+			Message: fmt.Sprintf("query '%s' returned %d outputs, unwrapping requires exactly one", cfg.Query, len(outputs)),
+		}
+	}
+
+	return outputs[0], nil
+}
+
+// toGeneric converts the value to its JSON representation made of maps, slices and scalars, with the numbers as json.Number.
+func toGeneric(value any) (any, error) {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(valueJSON))
+	decoder.UseNumber()
+
+	var generic any
+	if err = decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
+
+type queryToken struct {
+	kind  string
+	value string
+	pos   int
+}
+
+const (
+	tokenIdent    = "ident"
+	tokenString   = "string"
+	tokenNumber   = "number"
+	tokenOperator = "operator"
+)
+
+func tokenizeQuery(expression string) ([]queryToken, error) {
+	tokens := make([]queryToken, 0)
+	runes := []rune(expression)
+
+	for pos := 0; pos < len(runes); {
+		char := runes[pos]
+
+		switch {
+		case unicode.IsSpace(char):
+			pos++
+		case char == '"':
+			end := pos + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+
+				end++
+			}
+
+			if end >= len(runes) {
+				return nil, &errors.CommonError{Message: fmt.Sprintf("unterminated string in query '%s'", expression)}
+			}
+
+			value, err := strconv.Unquote(string(runes[pos : end+1]))
+			if err != nil {
+				return nil, &errors.CommonError{Message: fmt.Sprintf("invalid string in query '%s': %v", expression, err)}
+			}
+
+			tokens = append(tokens, queryToken{kind: tokenString, value: value, pos: pos})
+			pos = end + 1
+		case unicode.IsDigit(char):
+			end := pos
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+
+			tokens = append(tokens, queryToken{kind: tokenNumber, value: string(runes[pos:end]), pos: pos})
+			pos = end
+		case unicode.IsLetter(char) || char == '_':
+			end := pos
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '-') {
+				end++
+			}
+
+			tokens = append(tokens, queryToken{kind: tokenIdent, value: string(runes[pos:end]), pos: pos})
+			pos = end
+		default:
+			operator := string(char)
+			if pos+1 < len(runes) {
+				switch twoChars := string(runes[pos : pos+2]); twoChars {
+				case "==", "!=", "<=", ">=", "..":
+					operator = twoChars
+				}
+			}
+
+			if !strings.Contains("|,.[]{}():<>=!-", string(char)) {
+				return nil, &errors.CommonError{Message: fmt.Sprintf("unexpected character '%c' at position %d in query '%s'", char, pos, expression)}
+			}
+
+			tokens = append(tokens, queryToken{kind: tokenOperator, value: operator, pos: pos})
+			pos += len([]rune(operator))
+		}
+	}
+
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens     []queryToken
+	expression string
+	current    int
+}
+
+func (parser *queryParser) done() bool {
+	return parser.current >= len(parser.tokens)
+}
+
+func (parser *queryParser) peek() queryToken {
+	if parser.done() {
+		return queryToken{}
+	}
+
+	return parser.tokens[parser.current]
+}
+
+func (parser *queryParser) next() queryToken {
+	token := parser.peek()
+	parser.current++
+
+	return token
+}
+
+func (parser *queryParser) isOperator(value string) bool {
+	token := parser.peek()
+
+	return token.kind == tokenOperator && token.value == value
+}
+
+func (parser *queryParser) isKeyword(value string) bool {
+	token := parser.peek()
+
+	return token.kind == tokenIdent && token.value == value
+}
+
+func (parser *queryParser) expect(value string) error {
+	if !parser.isOperator(value) {
+		return parser.errorf("expected '%s'", value)
+	}
+
+	parser.next()
+
+	return nil
+}
+
+func (parser *queryParser) errorf(format string, args ...any) error {
+	position := len(parser.expression)
+	if !parser.done() {
+		position = parser.peek().pos
+	}
+
+	return &errors.CommonError{
+		Message: fmt.Sprintf("invalid query '%s' at position %d: %s", parser.expression, position, fmt.Sprintf(format, args...)),
+	}
+}
+
+func (parser *queryParser) parsePipe() (queryNode, error) {
+	left, err := parser.parseComma()
+	if err != nil {
+		return nil, err
+	}
+
+	for parser.isOperator("|") {
+		parser.next()
+
+		right, err := parser.parseComma()
+		if err != nil {
+			return nil, err
+		}
+
+		left = pipeNode(left, right)
+	}
+
+	return left, nil
+}
+
+func (parser *queryParser) parseComma() (queryNode, error) {
+	left, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	for parser.isOperator(",") {
+		parser.next()
+
+		right, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		left = commaNode(left, right)
+	}
+
+	return left, nil
+}
+
+func (parser *queryParser) parseOr() (queryNode, error) {
+	left, err := parser.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for parser.isKeyword("or") {
+		parser.next()
+
+		right, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = booleanNode(left, right, false)
+	}
+
+	return left, nil
+}
+
+func (parser *queryParser) parseAnd() (queryNode, error) {
+	left, err := parser.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for parser.isKeyword("and") {
+		parser.next()
+
+		right, err := parser.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+
+		left = booleanNode(left, right, true)
+	}
+
+	return left, nil
+}
+
+func (parser *queryParser) parseComparison() (queryNode, error) {
+	left, err := parser.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !parser.isOperator(operator) {
+			continue
+		}
+
+		parser.next()
+
+		right, err := parser.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+
+		return comparisonNode(left, right, operator), nil
+	}
+
+	return left, nil
+}
+
+func (parser *queryParser) parsePostfix() (queryNode, error) {
+	node, err := parser.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case parser.isOperator(".") && parser.current+1 < len(parser.tokens) &&
+			(parser.tokens[parser.current+1].kind == tokenIdent || parser.tokens[parser.current+1].kind == tokenString):
+			parser.next()
+			node = pipeNode(node, fieldNode(parser.next().value))
+		case parser.isOperator(".") && parser.current+1 < len(parser.tokens) && parser.tokens[parser.current+1].value == "[":
+			// yq style index selection, ex: .pipelines.[0]
+			parser.next()
+		case parser.isOperator("["):
+			suffix, err := parser.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+
+			node = pipeNode(node, suffix)
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (parser *queryParser) parsePrimary() (queryNode, error) {
+	token := parser.peek()
+
+	switch {
+	case parser.done():
+		return nil, parser.errorf("unexpected end of query")
+	case token.kind == tokenOperator && token.value == ".":
+		parser.next()
+
+		next := parser.peek()
+		if next.kind == tokenIdent || next.kind == tokenString {
+			parser.next()
+
+			return fieldNode(next.value), nil
+		}
+
+		return identityNode, nil
+	case token.kind == tokenOperator && token.value == "..":
+		parser.next()
+
+		return recurseNode, nil
+	case token.kind == tokenOperator && token.value == "(":
+		parser.next()
+
+		node, err := parser.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+
+		return node, parser.expect(")")
+	case token.kind == tokenOperator && token.value == "[":
+		return parser.parseArray()
+	case token.kind == tokenOperator && token.value == "{":
+		return parser.parseObject()
+	case token.kind == tokenOperator && token.value == "-":
+		parser.next()
+
+		number := parser.next()
+		if number.kind != tokenNumber {
+			return nil, parser.errorf("expected number after '-'")
+		}
+
+		return literalNode("-"+number.value, true)
+	case token.kind == tokenNumber || token.kind == tokenString:
+		parser.next()
+
+		return literalNode(token.value, token.kind == tokenNumber)
+	case token.kind == tokenIdent:
+		return parser.parseFunction()
+	default:
+		return nil, parser.errorf("unexpected '%s'", token.value)
+	}
+}
+
+func (parser *queryParser) parseBracket() (queryNode, error) {
+	parser.next()
+
+	if parser.isOperator("]") {
+		parser.next()
+
+		return iterateNode, nil
+	}
+
+	var start, end queryNode
+
+	if !parser.isOperator(":") {
+		node, err := parser.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+
+		start = node
+	}
+
+	if !parser.isOperator(":") {
+		return indexNode(start), parser.expect("]")
+	}
+
+	parser.next()
+
+	if !parser.isOperator("]") {
+		node, err := parser.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+
+		end = node
+	}
+
+	return sliceNode(start, end), parser.expect("]")
+}
+
+func (parser *queryParser) parseArray() (queryNode, error) {
+	parser.next()
+
+	if parser.isOperator("]") {
+		parser.next()
+
+		return func(_ any) ([]any, error) { return []any{[]any{}}, nil }, nil
+	}
+
+	node, err := parser.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+
+	return arrayOf(node), parser.expect("]")
+}
+
+func (parser *queryParser) parseObject() (queryNode, error) {
+	parser.next()
+
+	type objectField struct {
+		key   string
+		value queryNode
+	}
+
+	fields := make([]objectField, 0)
+
+	for !parser.isOperator("}") {
+		key := parser.next()
+		if key.kind != tokenIdent && key.kind != tokenString {
+			return nil, parser.errorf("expected object key")
+		}
+
+		value := fieldNode(key.value)
+
+		if parser.isOperator(":") {
+			parser.next()
+
+			node, err := parser.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			value = node
+		}
+
+		fields = append(fields, objectField{key: key.value, value: value})
+
+		if !parser.isOperator(",") {
+			break
+		}
+
+		parser.next()
+	}
+
+	if err := parser.expect("}"); err != nil {
+		return nil, err
+	}
+
+	return func(input any) ([]any, error) {
+		objects := []map[string]any{{}}
+
+		for _, field := range fields {
+			values, err := field.value(input)
+			if err != nil {
+				return nil, err
+			}
+
+			expanded := make([]map[string]any, 0, len(objects)*len(values))
+
+			for _, object := range objects {
+				for _, value := range values {
+					newObject := make(map[string]any, len(object)+1)
+					for key, existing := range object {
+						newObject[key] = existing
+					}
+
+					newObject[field.key] = value
+					expanded = append(expanded, newObject)
+				}
+			}
+
+			objects = expanded
+		}
+
+		outputs := make([]any, 0, len(objects))
+		for _, object := range objects {
+			outputs = append(outputs, object)
+		}
+
+		return outputs, nil
+	}, nil
+}
+
+func (parser *queryParser) parseFunction() (queryNode, error) {
+	name := parser.next().value
+
+	switch name {
+	case "true", "false":
+		return constantNode(name == "true"), nil
+	case "null":
+		return constantNode(nil), nil
+	case "keys":
+		return keysNode, nil
+	case "length":
+		return lengthNode, nil
+	case "not":
+		return notNode, nil
+	case "map", "select", "has":
+		if err := parser.expect("("); err != nil {
+			return nil, err
+		}
+
+		argument, err := parser.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = parser.expect(")"); err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case "map":
+			return arrayOf(pipeNode(iterateNode, argument)), nil
+		case "select":
+			return selectNode(argument), nil
+		default:
+			return hasNode(argument), nil
+		}
+	default:
+		return nil, parser.errorf("unknown function '%s'", name)
+	}
+}
+
+func identityNode(input any) ([]any, error) {
+	return []any{input}, nil
+}
+
+func constantNode(value any) queryNode {
+	return func(_ any) ([]any, error) {
+		return []any{value}, nil
+	}
+}
+
+func literalNode(value string, number bool) (queryNode, error) {
+	if !number {
+		return constantNode(value), nil
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return nil, &errors.CommonError{Message: fmt.Sprintf("invalid number '%s' in query", value)}
+	}
+
+	return constantNode(json.Number(value)), nil
+}
+
+func pipeNode(left, right queryNode) queryNode {
+	return func(input any) ([]any, error) {
+		leftOutputs, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs := make([]any, 0, len(leftOutputs))
+
+		for _, leftOutput := range leftOutputs {
+			rightOutputs, err := right(leftOutput)
+			if err != nil {
+				return nil, err
+			}
+
+			outputs = append(outputs, rightOutputs...)
+		}
+
+		return outputs, nil
+	}
+}
+
+func commaNode(left, right queryNode) queryNode {
+	return func(input any) ([]any, error) {
+		leftOutputs, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+
+		rightOutputs, err := right(input)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(leftOutputs, rightOutputs...), nil
+	}
+}
+
+func arrayOf(node queryNode) queryNode {
+	return func(input any) ([]any, error) {
+		outputs, err := node(input)
+		if err != nil {
+			return nil, err
+		}
+
+		return []any{append([]any{}, outputs...)}, nil
+	}
+}
+
+func fieldNode(name string) queryNode {
+	return func(input any) ([]any, error) {
+		switch object := input.(type) {
+		case nil:
+			return []any{nil}, nil
+		case map[string]any:
+			return []any{object[name]}, nil
+		default:
+			return nil, &errors.CommonError{Message: fmt.Sprintf("cannot index %s with '%s'", queryTypeName(input), name)}
+		}
+	}
+}
+
+func indexNode(index queryNode) queryNode {
+	return func(input any) ([]any, error) {
+		indexes, err := index(input)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs := make([]any, 0, len(indexes))
+
+		for _, indexValue := range indexes {
+			switch key := indexValue.(type) {
+			case string:
+				fieldOutputs, err := fieldNode(key)(input)
+				if err != nil {
+					return nil, err
+				}
+
+				outputs = append(outputs, fieldOutputs...)
+			case float64, json.Number:
+				list, ok := input.([]any)
+				if !ok && input != nil {
+					return nil, &errors.CommonError{Message: fmt.Sprintf("cannot index %s with number", queryTypeName(input))}
+				}
+
+				number, _ := queryNumber(key)
+				position := int(number)
+				if position < 0 {
+					position += len(list)
+				}
+
+				if position < 0 || position >= len(list) {
+					outputs = append(outputs, nil)
+
+					continue
+				}
+
+				outputs = append(outputs, list[position])
+			default:
+				return nil, &errors.CommonError{Message: fmt.Sprintf("cannot index %s with %s", queryTypeName(input), queryTypeName(indexValue))}
+			}
+		}
+
+		return outputs, nil
+	}
+}
+
+func sliceNode(start, end queryNode) queryNode {
+	bound := func(node queryNode, input any, length, fallback int) (int, error) {
+		if node == nil {
+			return fallback, nil
+		}
+
+		outputs, err := node(input)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(outputs) != 1 {
+			return 0, &errors.CommonError{Message: "slice bounds must be a single number"}
+		}
+
+		number, ok := queryNumber(outputs[0])
+		if !ok {
+			return 0, &errors.CommonError{Message: "slice bounds must be numbers"}
+		}
+
+		position := int(number)
+		if position < 0 {
+			position += length
+		}
+
+		return min(max(position, 0), length), nil
+	}
+
+	return func(input any) ([]any, error) {
+		var length int
+
+		switch value := input.(type) {
+		case nil:
+			return []any{nil}, nil
+		case []any:
+			length = len(value)
+		case string:
+			length = len([]rune(value))
+		default:
+			return nil, &errors.CommonError{Message: fmt.Sprintf("cannot slice %s", queryTypeName(input))}
+		}
+
+		from, err := bound(start, input, length, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := bound(end, input, length, length)
+		if err != nil {
+			return nil, err
+		}
+
+		to = max(from, to)
+
+		if text, ok := input.(string); ok {
+			return []any{string([]rune(text)[from:to])}, nil
+		}
+
+		return []any{append([]any{}, input.([]any)[from:to]...)}, nil
+	}
+}
+
+func iterateNode(input any) ([]any, error) {
+	switch value := input.(type) {
+	case []any:
+		return value, nil
+	case map[string]any:
+		outputs := make([]any, 0, len(value))
+		for _, key := range sortedMapKeys(value) {
+			outputs = append(outputs, value[key])
+		}
+
+		return outputs, nil
+	default:
+		return nil, &errors.CommonError{Message: fmt.Sprintf("cannot iterate over %s", queryTypeName(input))}
+	}
+}
+
+func recurseNode(input any) ([]any, error) {
+	outputs := []any{input}
+
+	switch value := input.(type) {
+	case []any, map[string]any:
+		children, err := iterateNode(value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			descendants, err := recurseNode(child)
+			if err != nil {
+				return nil, err
+			}
+
+			outputs = append(outputs, descendants...)
+		}
+	}
+
+	return outputs, nil
+}
+
+func keysNode(input any) ([]any, error) {
+	switch value := input.(type) {
+	case map[string]any:
+		keys := make([]any, 0, len(value))
+		for _, key := range sortedMapKeys(value) {
+			keys = append(keys, key)
+		}
+
+		return []any{keys}, nil
+	case []any:
+		keys := make([]any, 0, len(value))
+		for index := range value {
+			keys = append(keys, json.Number(strconv.Itoa(index)))
+		}
+
+		return []any{keys}, nil
+	default:
+		return nil, &errors.CommonError{Message: fmt.Sprintf("%s has no keys", queryTypeName(input))}
+	}
+}
+
+func lengthNode(input any) ([]any, error) {
+	switch value := input.(type) {
+	case nil:
+		return []any{json.Number("0")}, nil
+	case bool:
+		return nil, &errors.CommonError{Message: "boolean has no length"}
+	case float64:
+		if value < 0 {
+			return []any{-value}, nil
+		}
+
+		return []any{value}, nil
+	case json.Number:
+		return []any{json.Number(strings.TrimPrefix(value.String(), "-"))}, nil
+	case string:
+		return []any{json.Number(strconv.Itoa(len([]rune(value))))}, nil
+	case []any:
+		return []any{json.Number(strconv.Itoa(len(value)))}, nil
+	case map[string]any:
+		return []any{json.Number(strconv.Itoa(len(value)))}, nil
+	default:
+		return nil, &errors.CommonError{Message: fmt.Sprintf("%s has no length", queryTypeName(input))}
+	}
+}
+
+func notNode(input any) ([]any, error) {
+	return []any{!isTruthy(input)}, nil
+}
+
+func hasNode(key queryNode) queryNode {
+	return func(input any) ([]any, error) {
+		keys, err := key(input)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs := make([]any, 0, len(keys))
+
+		for _, keyValue := range keys {
+			switch value := input.(type) {
+			case map[string]any:
+				name, ok := keyValue.(string)
+				if !ok {
+					return nil, &errors.CommonError{Message: "object keys must be strings"}
+				}
+
+				_, found := value[name]
+				outputs = append(outputs, found)
+			case []any:
+				index, ok := queryNumber(keyValue)
+				if !ok {
+					return nil, &errors.CommonError{Message: "array indexes must be numbers"}
+				}
+
+				outputs = append(outputs, index >= 0 && int(index) < len(value))
+			default:
+				return nil, &errors.CommonError{Message: fmt.Sprintf("cannot check whether %s has a key", queryTypeName(input))}
+			}
+		}
+
+		return outputs, nil
+	}
+}
+
+func selectNode(condition queryNode) queryNode {
+	return func(input any) ([]any, error) {
+		results, err := condition(input)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs := make([]any, 0)
+
+		for _, result := range results {
+			if isTruthy(result) {
+				outputs = append(outputs, input)
+			}
+		}
+
+		return outputs, nil
+	}
+}
+
+func booleanNode(left, right queryNode, and bool) queryNode {
+	return func(input any) ([]any, error) {
+		leftOutputs, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs := make([]any, 0, len(leftOutputs))
+
+		for _, leftOutput := range leftOutputs {
+			if isTruthy(leftOutput) != and {
+				outputs = append(outputs, !and)
+
+				continue
+			}
+
+			rightOutputs, err := right(input)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, rightOutput := range rightOutputs {
+				outputs = append(outputs, isTruthy(rightOutput))
+			}
+		}
+
+		return outputs, nil
+	}
+}
+
+func comparisonNode(left, right queryNode, operator string) queryNode {
+	return func(input any) ([]any, error) {
+		leftOutputs, err := left(input)
+		if err != nil {
+			return nil, err
+		}
+
+		rightOutputs, err := right(input)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs := make([]any, 0, len(leftOutputs)*len(rightOutputs))
+
+		for _, rightOutput := range rightOutputs {
+			for _, leftOutput := range leftOutputs {
+				order := compareValues(leftOutput, rightOutput)
+
+				var result bool
+
+				switch operator {
+				case "==":
+					result = order == 0
+				case "!=":
+					result = order != 0
+				case "<":
+					result = order < 0
+				case "<=":
+					result = order <= 0
+				case ">":
+					result = order > 0
+				case ">=":
+					result = order >= 0
+				}
+
+				outputs = append(outputs, result)
+			}
+		}
+
+		return outputs, nil
+	}
+}
+
+func isTruthy(value any) bool {
+	switch boolean := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return boolean
+	default:
+		return true
+	}
+}
+
+// compareValues orders the values the way jq does: null < false < true < numbers < strings < arrays < objects.
+func compareValues(left, right any) int {
+	leftRank, rightRank := queryTypeRank(left), queryTypeRank(right)
+	if leftRank != rightRank {
+		return leftRank - rightRank
+	}
+
+	switch leftValue := left.(type) {
+	case float64, json.Number:
+		return compareNumbers(leftValue, right)
+	case string:
+		return strings.Compare(leftValue, right.(string))
+	case []any:
+		rightValue := right.([]any)
+		for index := 0; index < len(leftValue) && index < len(rightValue); index++ {
+			if order := compareValues(leftValue[index], rightValue[index]); order != 0 {
+				return order
+			}
+		}
+
+		return len(leftValue) - len(rightValue)
+	case map[string]any:
+		if reflect.DeepEqual(left, right) {
+			return 0
+		}
+
+		return strings.Compare(fmt.Sprint(left), fmt.Sprint(right))
+	}
+
+	return 0
+}
+
+// compareNumbers compares the integers exactly and the rest as float64.
+func compareNumbers(left, right any) int {
+	leftNumber, leftIsNumber := left.(json.Number)
+	rightNumber, rightIsNumber := right.(json.Number)
+
+	if leftIsNumber && rightIsNumber {
+		leftInteger, leftErr := leftNumber.Int64()
+		rightInteger, rightErr := rightNumber.Int64()
+
+		if leftErr == nil && rightErr == nil {
+			return cmp.Compare(leftInteger, rightInteger)
+		}
+	}
+
+	leftFloat, _ := queryNumber(left)
+	rightFloat, _ := queryNumber(right)
+
+	return compareOrdered(leftFloat, rightFloat)
+}
+
+// queryNumber returns the number as float64, reporting false when the value is not a number.
+func queryNumber(value any) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case json.Number:
+		float, err := number.Float64()
+
+		return float, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func compareOrdered(left, right float64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func queryTypeRank(value any) int {
+	switch typed := value.(type) {
+	case nil:
+		return 0
+	case bool:
+		if typed {
+			return 2
+		}
+
+		return 1
+	case float64, json.Number:
+		return 3
+	case string:
+		return 4
+	case []any:
+		return 5
+	default:
+		return 6
+	}
+}
+
+func queryTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func sortedMapKeys(value map[string]any) []string {
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,197 @@
+package renderer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pipelineStatus struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Counter int      `json:"counter"`
+	Stages  []string `json:"stages"`
+}
+
+var pipelineStatuses = map[string]any{
+	"group": "sample",
+	"pipelines": []pipelineStatus{
+		{Name: "build", Status: "Passed", Counter: 10, Stages: []string{"compile", "test"}},
+		{Name: "deploy", Status: "Failed", Counter: 3, Stages: []string{"deploy"}},
+		{Name: "release", Status: "Building", Counter: 7, Stages: []string{}},
+	},
+}
+
+func TestQuery_Evaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   []any
+	}{
+		{name: "identity", expression: ".group", expected: []any{"sample"}},
+		{name: "index", expression: ".pipelines[1].name", expected: []any{"deploy"}},
+		{name: "negative index", expression: ".pipelines[-1].name", expected: []any{"release"}},
+		{name: "yq style index", expression: ".pipelines.[0].name", expected: []any{"build"}},
+		{name: "quoted field", expression: `.["group"]`, expected: []any{"sample"}},
+		{name: "iterate", expression: ".pipelines[].name", expected: []any{"build", "deploy", "release"}},
+		{name: "slice", expression: ".pipelines[1:] | length", expected: []any{int64(2)}},
+		{name: "keys", expression: "keys", expected: []any{[]any{"group", "pipelines"}}},
+		{name: "length", expression: ".pipelines | length", expected: []any{int64(3)}},
+		{name: "map", expression: ".pipelines | map(.counter)", expected: []any{[]any{int64(10), int64(3), int64(7)}}},
+		{
+			name:       "select",
+			expression: `.pipelines[] | select(.status == "Failed" or .counter > 8) | .name`,
+			expected:   []any{"build", "deploy"},
+		},
+		{name: "select with and", expression: `.pipelines[] | select(.counter >= 3 and (.stages | length) == 0) | .name`, expected: []any{"release"}},
+		{name: "not", expression: `.pipelines[] | select(.status == "Passed" | not) | .name`, expected: []any{"deploy", "release"}},
+		{name: "has", expression: `.pipelines[0] | has("stages")`, expected: []any{true}},
+		{name: "object construction", expression: `.pipelines[0] | {name, latest: .counter}`, expected: []any{map[string]any{"name": "build", "latest": int64(10)}}},
+		{name: "array construction", expression: `[.pipelines[].stages[]]`, expected: []any{[]any{"compile", "test", "deploy"}}},
+		{name: "comma", expression: `.group, (.pipelines | length)`, expected: []any{"sample", int64(3)}},
+		{name: "recurse", expression: `[.. | select(. == "deploy")] | length`, expected: []any{int64(2)}},
+		{name: "missing field", expression: ".missing.field", expected: []any{nil}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := renderer.ParseQuery(test.expression)
+			require.NoError(t, err)
+
+			actual, err := query.Evaluate(pipelineStatuses)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	t.Run("should error for invalid query", func(t *testing.T) {
+		_, err := renderer.ParseQuery(".pipelines[")
+		require.EqualError(t, err, "invalid query '.pipelines[' at position 11: unexpected end of query")
+
+		_, err = renderer.ParseQuery("sort_by(.name)")
+		require.EqualError(t, err, "invalid query 'sort_by(.name)' at position 7: unknown function 'sort_by'")
+	})
+
+	t.Run("should error when indexing a scalar", func(t *testing.T) {
+		query, err := renderer.ParseQuery(".group.name")
+		require.NoError(t, err)
+
+		_, err = query.Evaluate(pipelineStatuses)
+		require.EqualError(t, err, "cannot index string with 'name'")
+	})
+}
+
+func TestConfig_Query(t *testing.T) {
+	query := `.pipelines[] | select(.status != "Passed") | {name, status}`
+
+	t.Run("should filter the value before rendering json", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		render.Query = query
+
+		err := render.Render(pipelineStatuses)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name":"deploy","status":"Failed"},{"name":"release","status":"Building"}]`, strReader.String())
+	})
+
+	t.Run("should filter the value before rendering yaml", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, true, false, false, false)
+		render.Query = query
+
+		err := render.Render(pipelineStatuses)
+		require.NoError(t, err)
+		assert.Equal(t, "---\n  - name: deploy\n    status: Failed\n  - name: release\n    status: Building\n", strReader.String())
+	})
+
+	t.Run("should keep the integers when rendering yaml", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, true, false, false, false)
+		render.Query = `.[] | select(.id > 9007199254740992 and .ratio == 0.5)`
+
+		err := render.Render([]map[string]any{{"id": int64(9007199254740993), "ratio": 0.5}, {"id": int64(9007199254740992), "ratio": 0.5}})
+		require.NoError(t, err)
+		assert.Equal(t, "---\n  - id: 9007199254740993\n    ratio: 0.5\n", strReader.String())
+	})
+
+	t.Run("should filter the value before rendering csv", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, true, false)
+		render.Query = query
+
+		err := render.Render(pipelineStatuses)
+		require.NoError(t, err)
+		assert.Equal(t, "name,status\ndeploy,Failed\nrelease,Building\n", strReader.String())
+	})
+
+	t.Run("should filter the value before rendering table", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, true)
+		render.Query = query
+
+		err := render.Render(pipelineStatuses)
+		require.NoError(t, err)
+		assert.Contains(t, strReader.String(), "Building")
+		assert.NotContains(t, strReader.String(), "Passed")
+	})
+
+	t.Run("should render multiple outputs as a list", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		render.Query = ".pipelines[].name"
+
+		err := render.Render(pipelineStatuses)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["build","deploy","release"]`, strReader.String())
+	})
+
+	t.Run("should render a single output as a list", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		render.Query = ".pipelines[0].name"
+
+		err := render.Render(pipelineStatuses)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["build"]`, strReader.String())
+	})
+
+	t.Run("should render the only output as is when unwrapping", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		render.Query = `.pipelines | map(select(.status != "Passed") | {name, status})`
+		render.QueryUnwrap = true
+
+		err := render.Render(pipelineStatuses)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name":"deploy","status":"Failed"},{"name":"release","status":"Building"}]`, strReader.String())
+	})
+
+	t.Run("should error when unwrapping multiple outputs", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, true, false, false)
+		render.Query = ".pipelines[].name"
+		render.QueryUnwrap = true
+
+		err := render.Render(pipelineStatuses)
+		require.EqualError(t, err, "query '.pipelines[].name' returned 3 outputs, unwrapping requires exactly one")
+	})
+
+	t.Run("should error for invalid query", func(t *testing.T) {
+		render := renderer.GetRenderer(new(bytes.Buffer), logrus.New(), true, false, true, false, false)
+		render.Query = ".pipelines[]]"
+
+		err := render.Render(pipelineStatuses)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,366 @@
+package renderer
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSliceSeparator = "|"
+	keyColumn             = "key"
+	valueColumn           = "value"
+)
+
+// records holds the tabular representation of a value, shared by the CSV and table renderers.
+// Values are kept as is until they are written so that they can be formatted per column.
+type records struct {
+	headers []string
+	rows    []map[string]any
+	seen    map[string]bool
+	// formats holds the format of the columns identified from the `render` struct tags.
+	formats    map[string]string
+	formatters map[string]cellFormatter
+	// keyed is set for key/value rows, where the format of the value is selected by the key.
+	keyed bool
+}
+
+func newRecords() *records {
+	return &records{seen: make(map[string]bool), formats: make(map[string]string)}
+}
+
+func (rec *records) addHeader(header string) {
+	if rec.seen[header] {
+		return
+	}
+
+	rec.seen[header] = true
+	rec.headers = append(rec.headers, header)
+}
+
+// selectColumns restricts the records to the columns passed, in the same order.
+func (rec *records) selectColumns(columns []string) {
+	if len(columns) == 0 {
+		return
+	}
+
+	rec.headers = columns
+}
+
+// cells returns the row as string cells in the order of the headers.
+func (rec *records) cells(row map[string]any, sliceSeparator string) []string {
+	cells := make([]string, 0, len(rec.headers))
+	for _, header := range rec.headers {
+		cells = append(cells, rec.formatCell(row, header, sliceSeparator))
+	}
+
+	return cells
+}
+
+// formatCell formats the cell with the formatter of its column when set, falling back to the raw value for the values it does not support.
+func (rec *records) formatCell(row map[string]any, header, sliceSeparator string) string {
+	column := header
+	if rec.keyed && header == valueColumn {
+		column = fmt.Sprint(row[keyColumn])
+	}
+
+	if formatter, ok := rec.formatters[column]; ok {
+		if formatted, ok := formatter(row[header]); ok {
+			return formatted
+		}
+	}
+
+	return formatCell(row[header], sliceSeparator)
+}
+
+// tabulate converts the value to records, deterministically ordering the columns.
+// Slices are rendered as a row per element, where structs and maps contribute their fields and keys as columns (union of all elements)
+// and every other element is rendered under the column 'value'.
+// A single struct is rendered as key/value rows, a single map as a row with keys as columns or as key/value rows when mapAsRows is set.
+// Nested structs and maps are flattened to dotted columns when flatten is set.
+func tabulate(value any, flatten, mapAsRows bool) *records {
+	rec := newRecords()
+	reflectValue := indirect(reflect.ValueOf(value))
+
+	switch reflectValue.Kind() {
+	case reflect.Invalid:
+		return rec
+	case reflect.Slice, reflect.Array:
+		if _, ok := value.([]byte); ok {
+			break
+		}
+
+		for index := range reflectValue.Len() {
+			rec.addRow(reflectValue.Index(index), flatten)
+		}
+
+		return rec
+	case reflect.Struct:
+		if !isLeaf(reflectValue) {
+			rec.addRow(reflectValue, flatten)
+
+			return rec.keyValues()
+		}
+	case reflect.Map:
+		rec.addRow(reflectValue, flatten)
+
+		if mapAsRows {
+			return rec.keyValues()
+		}
+
+		return rec
+	default:
+	}
+
+	rec.addRow(reflectValue, flatten)
+
+	return rec
+}
+
+func (rec *records) addRow(value reflect.Value, flatten bool) {
+	row := make(map[string]any)
+	value = indirect(value)
+
+	switch {
+	case isLeaf(value):
+		rec.flattenValue(row, valueColumn, value, false)
+	case value.Kind() == reflect.Struct:
+		rec.flattenStruct(row, "", value, flatten)
+	default:
+		for _, key := range sortedKeys(value) {
+			rec.flattenValue(row, fmt.Sprint(key.Interface()), indirect(value.MapIndex(key)), flatten)
+		}
+	}
+
+	rec.rows = append(rec.rows, row)
+}
+
+// keyValues converts the records to key/value rows, where each column becomes a row.
+func (rec *records) keyValues() *records {
+	keyValues := newRecords()
+	keyValues.formats = rec.formats
+	keyValues.keyed = true
+	keyValues.addHeader(keyColumn)
+	keyValues.addHeader(valueColumn)
+
+	for _, row := range rec.rows {
+		for _, header := range rec.headers {
+			keyValues.rows = append(keyValues.rows, map[string]any{keyColumn: header, valueColumn: row[header]})
+		}
+	}
+
+	return keyValues
+}
+
+func (rec *records) flattenStruct(row map[string]any, prefix string, value reflect.Value, flatten bool) {
+	valueType := value.Type()
+
+	for index := range valueType.NumField() {
+		field := valueType.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := fieldName(field)
+		if skip {
+			continue
+		}
+
+		if format, ok := field.Tag.Lookup(formatTag); ok {
+			rec.formats[prefix+name] = format
+		}
+
+		fieldValue := indirect(value.Field(index))
+
+		if field.Anonymous && !hasNameTag(field) && fieldValue.Kind() == reflect.Struct {
+			rec.flattenStruct(row, prefix, fieldValue, flatten)
+
+			continue
+		}
+
+		rec.flattenValue(row, prefix+name, fieldValue, flatten)
+	}
+}
+
+func (rec *records) flattenValue(row map[string]any, column string, value reflect.Value, flatten bool) {
+	if flatten && !isLeaf(value) {
+		switch value.Kind() {
+		case reflect.Struct:
+			rec.flattenStruct(row, column+".", value, flatten)
+
+			return
+		case reflect.Map:
+			for _, key := range sortedKeys(value) {
+				rec.flattenValue(row, fmt.Sprintf("%s.%v", column, key.Interface()), indirect(value.MapIndex(key)), flatten)
+			}
+
+			return
+		default:
+		}
+	}
+
+	rec.addHeader(column)
+
+	if value.IsValid() {
+		row[column] = value.Interface()
+	}
+}
+
+// fieldName returns the column name of the struct field, identified from csv tag, json tag or the field name in that order.
+func fieldName(field reflect.StructField) (string, bool) {
+	for _, tagName := range []string{"csv", "json"} {
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return "", true
+		}
+
+		if len(name) != 0 {
+			return name, false
+		}
+	}
+
+	return field.Name, false
+}
+
+func hasNameTag(field reflect.StructField) bool {
+	name, _ := fieldName(field)
+
+	return name != field.Name
+}
+
+// isGocsvSlice reports whether the value is a slice of structs whose columns gocsv names the same way as fieldName,
+// which could be rendered by gocsv.
+func isGocsvSlice(value any) bool {
+	valueType := reflect.TypeOf(value)
+	if valueType == nil || (valueType.Kind() != reflect.Slice && valueType.Kind() != reflect.Array) {
+		return false
+	}
+
+	elementType := valueType.Elem()
+	for elementType.Kind() == reflect.Pointer {
+		elementType = elementType.Elem()
+	}
+
+	return elementType.Kind() == reflect.Struct && namedByCSVTags(elementType, make(map[reflect.Type]bool))
+}
+
+// namedByCSVTags reports whether none of the fields of the struct, nested ones included, is named by its json tag,
+// as gocsv names the fields by csv tags or field names only.
+func namedByCSVTags(structType reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[structType] {
+		return true
+	}
+
+	visited[structType] = true
+
+	for index := range structType.NumField() {
+		field := structType.Field(index)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		if csvTag, _, _ := strings.Cut(field.Tag.Get("csv"), ","); len(csvTag) == 0 {
+			if jsonTag, _, _ := strings.Cut(field.Tag.Get("json"), ","); len(jsonTag) != 0 {
+				return false
+			}
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && !namedByCSVTags(fieldType, visited) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isLeaf reports whether the value should be rendered as a single cell even when flattening.
+func isLeaf(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+
+	if _, ok := value.Interface().(encoding.TextMarshaler); ok {
+		return true
+	}
+
+	if value.CanAddr() {
+		if _, ok := value.Addr().Interface().(encoding.TextMarshaler); ok {
+			return true
+		}
+	}
+
+	return value.Kind() != reflect.Struct && value.Kind() != reflect.Map
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+
+		value = value.Elem()
+	}
+
+	return value
+}
+
+func sortedKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	return keys
+}
+
+// formatCell returns the string representation of a single cell, slices are joined with the separator passed.
+func formatCell(value any, sliceSeparator string) string {
+	switch cell := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return cell
+	case []byte:
+		return string(cell)
+	case encoding.TextMarshaler:
+		text, err := cell.MarshalText()
+		if err != nil {
+			return fmt.Sprint(cell)
+		}
+
+		return string(text)
+	case float32:
+		return strconv.FormatFloat(float64(cell), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(cell, 'f', -1, 64)
+	}
+
+	reflectValue := indirect(reflect.ValueOf(value))
+	if !reflectValue.IsValid() {
+		return ""
+	}
+
+	if reflectValue.Kind() == reflect.Slice || reflectValue.Kind() == reflect.Array {
+		elements := make([]string, 0, reflectValue.Len())
+		for index := range reflectValue.Len() {
+			elements = append(elements, formatCell(reflectValue.Index(index).Interface(), sliceSeparator))
+		}
+
+		return strings.Join(elements, sliceSeparator)
+	}
+
+	return fmt.Sprint(reflectValue.Interface())
+}
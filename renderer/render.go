@@ -2,14 +2,15 @@ package renderer
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
 	"github.com/gocarina/gocsv"
-	"github.com/goccy/go-yaml"
+	"github.com/nikhilsbhat/common/content"
+	"github.com/nikhilsbhat/common/errors"
+	"github.com/nikhilsbhat/common/progress"
 	"github.com/olekukonko/tablewriter"
 	"github.com/sirupsen/logrus"
 )
@@ -20,9 +21,42 @@ type Config struct {
 	JSON    bool `json:"json,omitempty" yaml:"json,omitempty"`
 	CSV     bool `json:"csv,omitempty" yaml:"csv,omitempty"`
 	Table   bool `json:"table,omitempty" yaml:"table,omitempty"`
+	XML     bool `json:"xml,omitempty" yaml:"xml,omitempty"`
+	TOML    bool `json:"toml,omitempty" yaml:"toml,omitempty"`
+	Tree    bool `json:"tree,omitempty" yaml:"tree,omitempty"`
 	NoColor bool `json:"no_color,omitempty" yaml:"no_color,omitempty"`
-	writer  *bufio.Writer
-	logger  *logrus.Logger
+	Pager   bool `json:"pager,omitempty" yaml:"pager,omitempty"`
+	// Query is evaluated against the value before it is encoded to any format, see Query for the supported expressions.
+	Query string `json:"query,omitempty" yaml:"query,omitempty"`
+	// QueryUnwrap renders the only output of Config.Query as is, instead of the list of outputs rendered by default.
+	// Rendering fails when the query has no or several outputs.
+	QueryUnwrap bool `json:"query_unwrap,omitempty" yaml:"query_unwrap,omitempty"`
+	// Canonical renders equivalent values byte-identical, sorting the keys of structs and maps and normalizing the numbers.
+	Canonical bool `json:"canonical,omitempty" yaml:"canonical,omitempty"`
+	// SortListsBy sorts the lists of objects by the key set when Canonical is enabled.
+	SortListsBy string `json:"sort_lists_by,omitempty" yaml:"sort_lists_by,omitempty"`
+	// Encoding customises the output of ToJSON, ToYAML, ToXML and ToTOML.
+	Encoding content.EncodeOptions `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	// File customises the files written by RenderToFile.
+	File FileOptions `json:"file,omitempty" yaml:"file,omitempty"`
+	// MapAsRows renders a single map as key/value rows in CSV and table instead of a row with keys as columns.
+	MapAsRows bool `json:"map_as_rows,omitempty" yaml:"map_as_rows,omitempty"`
+	// CSVOptions customises the output of ToCSV.
+	CSVOptions CSVOptions `json:"csv_options,omitempty" yaml:"csv_options,omitempty"`
+	// Formats selects the format of the CSV and table cells by column name, ex: {"created": "age", "size": "size", "updated": "time:UTC"}.
+	// Fields of structs could select their format with the `render` struct tag as well, JSON and YAML are always rendered raw.
+	Formats map[string]string `json:"formats,omitempty" yaml:"formats,omitempty"`
+	// ColorRules colors the cells and rows of ToTable by their values, applied only when colors are enabled and the output is a terminal.
+	ColorRules []ColorRule `json:"color_rules,omitempty" yaml:"color_rules,omitempty"`
+	// TableBatchSize is the number of rows rendered at a time when rendering iter.Seq and iter.Seq2 values in table format, defaults to 100.
+	TableBatchSize int `json:"table_batch_size,omitempty" yaml:"table_batch_size,omitempty"`
+	// TreeOptions customises the output of ToTree.
+	TreeOptions TreeOptions `json:"tree_options,omitempty" yaml:"tree_options,omitempty"`
+	progress    *progress.Manager
+	writer      *bufio.Writer
+	output      io.Writer
+	input       io.Reader
+	logger      *logrus.Logger
 }
 
 // Renderer implements methods that Prints values in YAML,JSON,CSV and Table format.
@@ -33,8 +67,26 @@ type Renderer interface {
 	ToTable(value any) error
 }
 
-// Render renders the output based on the output format selection (toYAML, toJSON).
+// XMLRenderer is implemented by the renderers that Prints values in XML format as well, it is kept apart from Renderer
+// so that the existing implementations of Renderer are not required to implement it.
+type XMLRenderer interface {
+	ToXML(value any) error
+}
+
+// TOMLRenderer is implemented by the renderers that Prints values in TOML format as well, it is kept apart from Renderer for the same reason.
+type TOMLRenderer interface {
+	ToTOML(value any) error
+}
+
+// TreeRenderer is implemented by the renderers that Prints values as tree as well, it is kept apart from Renderer for the same reason.
+type TreeRenderer interface {
+	ToTree(value any) error
+}
+
+// Render renders the output based on the output format selection (toYAML, toJSON, toCSV, toTable, toXML, toTOML, toTree).
 // If none is selected it prints as the source.
+// iter.Seq and iter.Seq2 values are rendered lazily as JSON arrays, YAML sequences, CSV rows and table rows (iter.Seq2 as objects, mappings and key/value rows),
+// they are collected in full for the rest of the formats and when Config.Query or Config.Canonical is set.
 func (cfg *Config) Render(value any) error {
 	if cfg.JSON {
 		return cfg.ToJSON(value)
@@ -52,44 +104,54 @@ func (cfg *Config) Render(value any) error {
 		return cfg.ToTable(value)
 	}
 
+	if cfg.XML {
+		return cfg.ToXML(value)
+	}
+
+	if cfg.TOML {
+		return cfg.ToTOML(value)
+	}
+
+	if cfg.Tree {
+		return cfg.ToTree(value)
+	}
+
 	cfg.logger.Debug("no format was specified for rendering output to defaults")
 
-	_, err := cfg.writer.Write(fmt.Appendf(nil, "%v\n", value))
+	value, err := cfg.prepare(value)
 	if err != nil {
-		cfg.logger.Fatalln(err)
+		return err
 	}
 
-	defer func(writer *bufio.Writer) {
-		err = writer.Flush()
-		if err != nil {
-			cfg.logger.Fatalln(err)
-		}
-	}(cfg.writer)
+	value = materialize(value)
 
-	return nil
+	return cfg.write(fmt.Sprintf("%v\n", value))
 }
 
 // ToYAML renders the value to YAML format.
 func (cfg *Config) ToYAML(value any) error {
 	cfg.logger.Debug("rendering output in yaml format since Config.YAML is enabled")
 
-	yamlIndent := 2
+	value, err := cfg.prepare(value)
+	if err != nil {
+		return err
+	}
 
-	encodeOptions := []yaml.EncodeOption{
-		yaml.Indent(yamlIndent),
-		yaml.IndentSequence(true),
-		yaml.UseLiteralStyleIfMultiline(true),
+	if seq, ok := asSequence(value); ok && !cfg.Encoding.FlowStyle {
+		return cfg.streamPaged(seq, cfg.streamYAML)
 	}
 
-	valueYAML, err := yaml.MarshalWithOptions(value, encodeOptions...)
+	value = materialize(value)
+
+	valueYAML, err := cfg.Encoding.EncodeYAML(value)
 	if err != nil {
 		return err
 	}
 
-	yamlString := strings.Join([]string{"---", string(valueYAML)}, "\n")
+	yamlString := string(valueYAML)
 
 	if !cfg.NoColor {
-		coloredYAMLString, err := cfg.Color(TypeYAML, string(valueYAML))
+		coloredYAMLString, err := cfg.Color(TypeYAML, yamlString)
 		if err != nil {
 			return err
 		}
@@ -97,26 +159,23 @@ func (cfg *Config) ToYAML(value any) error {
 		yamlString = coloredYAMLString
 	}
 
-	_, err = cfg.writer.Write([]byte(yamlString))
-	if err != nil {
-		cfg.logger.Fatalln(err)
-	}
-
-	defer func(writer *bufio.Writer) {
-		err = writer.Flush()
-		if err != nil {
-			cfg.logger.Fatalln(err)
-		}
-	}(cfg.writer)
-
-	return nil
+	return cfg.write(yamlString)
 }
 
 // ToJSON renders the value to JSON format.
 func (cfg *Config) ToJSON(value any) error {
 	cfg.logger.Debug("rendering output in json format since Config.JSON is enabled")
 
-	valueJSON, err := json.MarshalIndent(value, "", "     ")
+	value, err := cfg.prepare(value)
+	if err != nil {
+		return err
+	}
+
+	if seq, ok := asSequence(value); ok {
+		return cfg.streamPaged(seq, cfg.streamJSON)
+	}
+
+	valueJSON, err := cfg.Encoding.EncodeJSON(value)
 	if err != nil {
 		return err
 	}
@@ -132,75 +191,230 @@ func (cfg *Config) ToJSON(value any) error {
 		jsonString = coloredJSONString
 	}
 
-	_, err = cfg.writer.Write([]byte(jsonString))
-	if err != nil {
-		cfg.logger.Fatalln(err)
-	}
-
-	defer func(writer *bufio.Writer) {
-		err = writer.Flush()
-		if err != nil {
-			cfg.logger.Fatalln(err)
-		}
-	}(cfg.writer)
-
-	return nil
+	return cfg.write(jsonString)
 }
 
 // ToCSV renders the value to CSV format.
+// The columns of structs are named by their csv tags, json tags or field names in that order, whichever way they are rendered.
+// Slices of structs are rendered with gocsv unless Config.CSVOptions or formats are set or their fields are named by json tags,
+// every other value (single structs, maps, slices of maps and slices with mixed elements) is rendered with deterministic column order.
+// The columns are colored when the output is a terminal, unless Config.NoColor is enabled.
 func (cfg *Config) ToCSV(value any) error {
 	cfg.logger.Debug("rendering output in csv format since Config.CSV is enabled")
 
-	csvString, err := gocsv.MarshalString(value)
+	value, err := cfg.prepare(value)
 	if err != nil {
 		return err
 	}
 
-	_, err = cfg.writer.Write([]byte(csvString))
+	if seq, ok := asSequence(value); ok {
+		return cfg.streamPaged(seq, cfg.streamCSV)
+	}
+
+	var csvString string
+	if !cfg.CSVOptions.isDefault() || !isGocsvSlice(value) || cfg.hasFormats(value) {
+		csvString, err = cfg.csvString(value)
+	} else {
+		csvString, err = gocsv.MarshalString(value)
+	}
+
 	if err != nil {
-		cfg.logger.Fatalln(err)
+		return err
 	}
 
-	defer func(writer *bufio.Writer) {
-		err = writer.Flush()
-		if err != nil {
-			cfg.logger.Fatalln(err)
+	if cfg.colored(TypeCSV) {
+		if csvString, err = cfg.Color(TypeCSV, csvString); err != nil {
+			return err
 		}
-	}(cfg.writer)
+	}
 
-	return nil
+	return cfg.write(csvString)
 }
 
 // ToTable renders the value to Table format.
+// [][]string is rendered as is, every other value is rendered with headers in the same way as ToCSV with nested values flattened.
+// The cells and rows are colored by Config.ColorRules, where the columns of [][]string are identified from its first row,
+// and the header is emphasised when the output is a terminal, unless Config.NoColor is enabled.
 func (cfg *Config) ToTable(value any) error {
 	cfg.logger.Debug("rendering output in table format since Config.ToTabled is enabled")
 
+	value, err := cfg.prepare(value)
+	if err != nil {
+		return err
+	}
+
+	if seq, ok := asSequence(value); ok {
+		return cfg.streamPaged(seq, cfg.streamTable)
+	}
+
 	tableString := &strings.Builder{}
-	table := tablewriter.NewWriter(tableString)
+	table := newTable(tableString)
+
+	colorRules, err := cfg.colorRules()
+	if err != nil {
+		return err
+	}
+
+	if rows, ok := value.([][]string); ok {
+		appendRows(table, rows, colorRules)
+	} else {
+		rec := tabulate(value, true, cfg.MapAsRows)
+		if err = cfg.formatRecords(rec); err != nil {
+			return err
+		}
+
+		appendRecords(table, rec, colorRules)
+	}
+
+	table.Render()
+
+	if !cfg.colored(TypeTable) {
+		return cfg.write(tableString.String())
+	}
+
+	return cfg.write(colorTable(tableString.String(), true))
+}
+
+// newTable returns the table writer with the layout used by ToTable.
+func newTable(writer io.Writer) *tablewriter.Table {
+	table := tablewriter.NewWriter(writer)
 
 	table.SetAlignment(tablewriter.ALIGN_CENTER)
 	table.SetAutoWrapText(true)
 	table.SetAutoMergeCells(true)
 	table.SetRowLine(true)
 
-	table.AppendBulk(value.([][]string))
-	table.Render()
+	return table
+}
+
+// appendRecords sets the headers and appends the rows of the records to the table, colored by the rules.
+func appendRecords(table *tablewriter.Table, rec *records, colorRules []ColorRule) {
+	table.SetHeader(rec.headers)
 
-	_, err := cfg.writer.Write([]byte(tableString.String()))
+	for _, row := range rec.rows {
+		cells := rec.cells(row, "\n")
+		table.Rich(cells, cellColors(colorRules, rec.headers, cells))
+	}
+}
+
+// appendRows appends the rows to the table as is, coloring all but the first row by the rules matching the columns named in the first row.
+func appendRows(table *tablewriter.Table, rows [][]string, colorRules []ColorRule) {
+	if len(colorRules) == 0 || len(rows) == 0 {
+		table.AppendBulk(rows)
+
+		return
+	}
+
+	table.Append(rows[0])
+
+	for _, cells := range rows[1:] {
+		table.Rich(cells, cellColors(colorRules, rows[0], cells))
+	}
+}
+
+// ToXML renders the value to XML format.
+func (cfg *Config) ToXML(value any) error {
+	cfg.logger.Debug("rendering output in xml format since Config.XML is enabled")
+
+	value, err := cfg.prepare(value)
 	if err != nil {
-		cfg.logger.Fatalln(err)
+		return err
 	}
 
-	defer func(writer *bufio.Writer) {
-		err = writer.Flush()
+	value = materialize(value)
+
+	valueXML, err := cfg.Encoding.EncodeXML(value)
+	if err != nil {
+		return err
+	}
+
+	xmlString := string(valueXML)
+
+	if !cfg.NoColor {
+		coloredXMLString, err := cfg.Color(TypeXML, xmlString)
 		if err != nil {
-			cfg.logger.Fatalln(err)
+			return err
 		}
-	}(cfg.writer)
+
+		xmlString = coloredXMLString
+	}
+
+	return cfg.write(xmlString)
+}
+
+// SetFormat selects the output format, one of TypeYAML, TypeJSON, TypeCSV, TypeTable, TypeXML, TypeTOML or TypeTree.
+// An empty format renders the value as is.
+func (cfg *Config) SetFormat(format string) error {
+	cfg.YAML, cfg.JSON, cfg.CSV, cfg.Table, cfg.XML, cfg.TOML, cfg.Tree = false, false, false, false, false, false, false
+
+	switch strings.ToLower(format) {
+	case "":
+	case TypeYAML:
+		cfg.YAML = true
+	case TypeJSON:
+		cfg.JSON = true
+	case TypeCSV:
+		cfg.CSV = true
+	case TypeTable:
+		cfg.Table = true
+	case TypeXML:
+		cfg.XML = true
+	case TypeTOML:
+		cfg.TOML = true
+	case TypeTree:
+		cfg.Tree = true
+	default:
+		return &errors.CommonError{Message: fmt.Sprintf("unknown format '%s', cannot render the value", format)}
+	}
 
 	return nil
 }
 
+// prepare processes the value before it is encoded, evaluating Config.Query and converting the value to canonical form if set.
+// Sequences are collected first in that case, since both need the complete value.
+func (cfg *Config) prepare(value any) (any, error) {
+	if seq, ok := asSequence(value); ok && (len(cfg.Query) != 0 || cfg.Canonical) {
+		value = seq.collect()
+	}
+
+	value, err := cfg.applyQuery(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.canonicalize(value)
+}
+
+// ToTOML renders the value to TOML format, the value should be a struct or a map.
+func (cfg *Config) ToTOML(value any) error {
+	cfg.logger.Debug("rendering output in toml format since Config.TOML is enabled")
+
+	value, err := cfg.prepare(value)
+	if err != nil {
+		return err
+	}
+
+	value = materialize(value)
+
+	valueTOML, err := cfg.Encoding.EncodeTOML(value)
+	if err != nil {
+		return err
+	}
+
+	tomlString := string(valueTOML)
+
+	if !cfg.NoColor {
+		coloredTOMLString, err := cfg.Color(TypeTOML, tomlString)
+		if err != nil {
+			return err
+		}
+
+		tomlString = coloredTOMLString
+	}
+
+	return cfg.write(tomlString)
+}
+
 // GetRenderer returns the new instance of Config.
 func GetRenderer(writer io.Writer, log *logrus.Logger, noColor, yaml, json, csv, table bool) Config {
 	renderer := Config{
@@ -210,13 +424,55 @@ func GetRenderer(writer io.Writer, log *logrus.Logger, noColor, yaml, json, csv,
 		CSV:     csv,
 		Table:   table,
 		NoColor: noColor,
+		output:  writer,
+		input:   os.Stdin,
 	}
 
 	if writer == nil {
-		renderer.writer = bufio.NewWriter(os.Stdout)
-	} else {
-		renderer.writer = bufio.NewWriter(writer)
+		renderer.output = os.Stdout
 	}
 
+	renderer.writer = bufio.NewWriter(renderer.output)
+
 	return renderer
 }
+
+// WithProgress attaches the progress manager to the renderer, which is stopped and cleared before the output is written.
+func (cfg *Config) WithProgress(manager *progress.Manager) *Config {
+	cfg.progress = manager
+
+	return cfg
+}
+
+// NewProgress returns a new progress manager drawing on the output of the renderer, attached as in WithProgress.
+// The manager is disabled when the output is not a terminal.
+func (cfg *Config) NewProgress() *progress.Manager {
+	cfg.progress = progress.NewManager(cfg.output, cfg.logger)
+
+	return cfg.progress
+}
+
+func (cfg *Config) stopProgress() error {
+	if cfg.progress == nil {
+		return nil
+	}
+
+	return cfg.progress.Stop()
+}
+
+// write writes the rendered output to the writer, the output is paged when Config.Pager is enabled and the output does not fit the terminal.
+func (cfg *Config) write(out string) error {
+	if err := cfg.stopProgress(); err != nil {
+		return err
+	}
+
+	if height, ok := cfg.pagerHeight(out); ok {
+		return cfg.page(out, height)
+	}
+
+	if _, err := cfg.writer.WriteString(out); err != nil {
+		return err
+	}
+
+	return cfg.writer.Flush()
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/nikhilsbhat/common/content"
+	"github.com/nikhilsbhat/common/progress"
 	"github.com/nikhilsbhat/common/prompt"
 	"github.com/nikhilsbhat/common/renderer"
 	"github.com/sirupsen/logrus"
@@ -84,6 +85,35 @@ func TestGetRenderer(t *testing.T) {
 		assert.Contains(t, strReader.String(), "500")
 	})
 
+	t.Run("should render the value with encoding options", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		logger := logrus.New()
+		render := renderer.GetRenderer(strReader, logger, true, true, false, false, false)
+		render.Encoding = content.EncodeOptions{NoDocumentSeparator: true, Indent: 4}
+
+		err := render.Render(map[string][]string{"stages": {"build"}})
+		require.NoError(t, err)
+		assert.Equal(t, "stages:\n    - build\n", strReader.String())
+	})
+
+	t.Run("should be able to render the value to xml successfully", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		logger := logrus.New()
+		render := renderer.GetRenderer(strReader, logger, false, false, false, false, false)
+		render.XML = true
+
+		inputOptions := []prompt.Options{{Name: "yes", Short: "y"}, {Name: "no", Short: "n"}}
+
+		err := render.Render(map[string]any{"options": inputOptions})
+		require.NoError(t, err)
+
+		obj := content.Object(strReader.String())
+		actual := obj.CheckFileType(logger)
+		assert.Equal(t, "xml", actual)
+	})
+
 	t.Run("should render in defaults since no render type was selected", func(t *testing.T) {
 		strReader := new(bytes.Buffer)
 
@@ -126,3 +156,74 @@ func TestGetRenderer(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestConfig_ToTable(t *testing.T) {
+	t.Run("should render slice of maps with headers", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		logger := logrus.New()
+		render := renderer.GetRenderer(strReader, logger, true, false, false, false, true)
+
+		err := render.Render([]map[string]any{
+			{"name": "api", "spec": map[string]any{"image": "api:v1"}},
+			{"name": "web", "replicas": 3},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, strReader.String(), "NAME")
+		assert.Contains(t, strReader.String(), "SPEC IMAGE")
+		assert.Contains(t, strReader.String(), "REPLICAS")
+		assert.Contains(t, strReader.String(), "api:v1")
+	})
+
+	t.Run("should render a single struct as key/value rows", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		logger := logrus.New()
+		render := renderer.GetRenderer(strReader, logger, true, false, false, false, true)
+
+		err := render.Render(prompt.Options{Name: "yes", Short: "y"})
+		require.NoError(t, err)
+		assert.Contains(t, strReader.String(), "KEY")
+		assert.Contains(t, strReader.String(), "short")
+	})
+
+	t.Run("should render a scalar without panicking", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		logger := logrus.New()
+		render := renderer.GetRenderer(strReader, logger, true, false, false, false, true)
+
+		err := render.Render("testing")
+		require.NoError(t, err)
+		assert.Contains(t, strReader.String(), "testing")
+	})
+}
+
+func TestConfig_WithProgress(t *testing.T) {
+	t.Run("should clear the progress before writing the output", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		manager := progress.NewManager(strReader, logrus.New()).WithEnabled(true)
+		render.WithProgress(manager)
+
+		manager.AddSpinner("exporting")
+		require.NoError(t, manager.Render())
+
+		err := render.Render(map[string]string{"name": "sample"})
+		require.NoError(t, err)
+		assert.Equal(t, "\x1b[2K⠋ exporting\n\x1b[1A\x1b[J{\n     \"name\": \"sample\"\n}", strReader.String())
+	})
+
+	t.Run("should disable progress when output is not a terminal", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, true, false, false)
+		manager := render.NewProgress()
+		manager.AddBar("export", 10)
+
+		require.NoError(t, manager.Render())
+		assert.False(t, manager.Enabled())
+		assert.Empty(t, strReader.String())
+	})
+}
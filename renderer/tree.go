@@ -0,0 +1,219 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/goccy/go-yaml"
+)
+
+// TypeTree identifies the tree format rendered by ToTree.
+const TypeTree = "tree"
+
+// treeConnectors holds the connectors drawn between the nodes of the tree.
+type treeConnectors struct {
+	branch, last, vertical, space, collapsed string
+}
+
+var (
+	unicodeConnectors = treeConnectors{branch: "├── ", last: "└── ", vertical: "│   ", space: "    ", collapsed: "…"}
+	asciiConnectors   = treeConnectors{branch: "|-- ", last: "`-- ", vertical: "|   ", space: "    ", collapsed: "..."}
+)
+
+// TreeOptions holds the options to customise the tree rendered by ToTree.
+type TreeOptions struct {
+	// MaxDepth limits the levels rendered, the nested values beyond are summarised with their size. Zero renders every level.
+	MaxDepth int `json:"max_depth,omitempty" yaml:"max_depth,omitempty"`
+	// LabelField is the field of the objects in lists used as their label, ex: name. Items are labelled by index otherwise.
+	LabelField string `json:"label_field,omitempty" yaml:"label_field,omitempty"`
+	// ASCII draws the connectors with ASCII characters for terminals without unicode support.
+	ASCII bool `json:"ascii,omitempty" yaml:"ascii,omitempty"`
+}
+
+// treeNode is a line of the tree along with its children.
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+// ToTree renders the value as a tree, where nested maps, slices and structs are drawn with box-drawing connectors.
+// Fields of structs and keys of maps retain their order as rendered by ToJSON.
+func (cfg *Config) ToTree(value any) error {
+	cfg.logger.Debug("rendering output in tree format since Config.Tree is enabled")
+
+	value, err := cfg.prepare(value)
+	if err != nil {
+		return err
+	}
+
+	ordered, err := orderedValue(materialize(value))
+	if err != nil {
+		return err
+	}
+
+	connectors := unicodeConnectors
+	if cfg.TreeOptions.ASCII {
+		connectors = asciiConnectors
+	}
+
+	root := &treeNode{label: "."}
+	if children, ok := cfg.treeChildren(ordered, 1, connectors); ok {
+		root.children = children
+	} else {
+		root.label = treeScalar(ordered)
+	}
+
+	connectorColor := color.New(color.Faint)
+	if cfg.NoColor {
+		connectorColor.DisableColor()
+	} else {
+		connectorColor.EnableColor()
+	}
+
+	var tree strings.Builder
+
+	tree.WriteString(root.label + "\n")
+	writeTree(&tree, root.children, "", connectors, connectorColor)
+
+	return cfg.write(tree.String())
+}
+
+// treeChildren returns the children of maps and slices, ok is false for the rest of the values which are leaves.
+func (cfg *Config) treeChildren(value any, depth int, connectors treeConnectors) ([]*treeNode, bool) {
+	switch typedValue := value.(type) {
+	case yaml.MapSlice:
+		children := make([]*treeNode, 0, len(typedValue))
+		for _, item := range typedValue {
+			children = append(children, cfg.treeNode(fmt.Sprint(item.Key), item.Value, depth, connectors))
+		}
+
+		return children, true
+	case []any:
+		children := make([]*treeNode, 0, len(typedValue))
+
+		for index, item := range typedValue {
+			label, remaining := cfg.itemLabel(index, item)
+			if remaining == nil {
+				children = append(children, &treeNode{label: label})
+
+				continue
+			}
+
+			children = append(children, cfg.treeNode(label, remaining, depth, connectors))
+		}
+
+		return children, true
+	default:
+		return nil, false
+	}
+}
+
+func (cfg *Config) treeNode(label string, value any, depth int, connectors treeConnectors) *treeNode {
+	switch typedValue := value.(type) {
+	case yaml.MapSlice:
+		if len(typedValue) == 0 {
+			return &treeNode{label: label + ": {}"}
+		}
+
+		if cfg.TreeOptions.MaxDepth > 0 && depth >= cfg.TreeOptions.MaxDepth {
+			return &treeNode{label: fmt.Sprintf("%s {%d keys %s}", label, len(typedValue), connectors.collapsed)}
+		}
+	case []any:
+		if len(typedValue) == 0 {
+			return &treeNode{label: label + ": []"}
+		}
+
+		if cfg.TreeOptions.MaxDepth > 0 && depth >= cfg.TreeOptions.MaxDepth {
+			return &treeNode{label: fmt.Sprintf("%s [%d items %s]", label, len(typedValue), connectors.collapsed)}
+		}
+	default:
+		return &treeNode{label: fmt.Sprintf("%s: %s", label, treeScalar(value))}
+	}
+
+	children, _ := cfg.treeChildren(value, depth+1, connectors)
+
+	return &treeNode{label: label, children: children}
+}
+
+// itemLabel returns the label of the list item, from TreeOptions.LabelField when set or the index, along with the value to be rendered under it.
+// remaining is nil when there is nothing to render under the label, as for scalar items which are labelled by their value.
+func (cfg *Config) itemLabel(index int, item any) (string, any) {
+	label := fmt.Sprintf("[%d]", index)
+
+	switch typedItem := item.(type) {
+	case yaml.MapSlice:
+		if len(cfg.TreeOptions.LabelField) == 0 {
+			return label, typedItem
+		}
+
+		labelled := false
+		remaining := make(yaml.MapSlice, 0, len(typedItem))
+
+		for _, field := range typedItem {
+			if fmt.Sprint(field.Key) == cfg.TreeOptions.LabelField && isScalar(field.Value) {
+				label, labelled = treeScalar(field.Value), true
+
+				continue
+			}
+
+			remaining = append(remaining, field)
+		}
+
+		if labelled && len(remaining) == 0 {
+			return label, nil
+		}
+
+		return label, remaining
+	case []any:
+		return label, typedItem
+	default:
+		return treeScalar(item), nil
+	}
+}
+
+func writeTree(tree *strings.Builder, nodes []*treeNode, prefix string, connectors treeConnectors, connectorColor *color.Color) {
+	for index, node := range nodes {
+		connector, childPrefix := connectors.branch, connectors.vertical
+		if index == len(nodes)-1 {
+			connector, childPrefix = connectors.last, connectors.space
+		}
+
+		tree.WriteString(connectorColor.Sprint(prefix+connector) + node.label + "\n")
+		writeTree(tree, node.children, prefix+childPrefix, connectors, connectorColor)
+	}
+}
+
+// orderedValue converts the value to generic maps and slices, retaining the order of struct fields through a JSON round trip.
+func orderedValue(value any) (any, error) {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var ordered any
+	if err = yaml.UnmarshalWithOptions(valueJSON, &ordered, yaml.UseOrderedMap()); err != nil {
+		return nil, err
+	}
+
+	return ordered, nil
+}
+
+func isScalar(value any) bool {
+	switch value.(type) {
+	case yaml.MapSlice, []any:
+		return false
+	default:
+		return true
+	}
+}
+
+// treeScalar returns the string representation of the leaves, null for missing values.
+func treeScalar(value any) string {
+	if value == nil {
+		return "null"
+	}
+
+	return formatCell(value, ",")
+}
@@ -0,0 +1,97 @@
+package renderer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nikhilsbhat/common/renderer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_ToTree(t *testing.T) {
+	group := map[string]any{
+		"group": "sample",
+		"pipelines": []pipelineStatus{
+			{Name: "build", Status: "Passed", Counter: 10, Stages: []string{"compile", "test"}},
+			{Name: "deploy", Status: "Failed", Counter: 3, Stages: []string{}},
+		},
+	}
+
+	t.Run("should render nested values as tree", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, false)
+		require.NoError(t, render.SetFormat(renderer.TypeTree))
+
+		err := render.Render(group)
+		require.NoError(t, err)
+		assert.Equal(t, `.
+├── group: sample
+└── pipelines
+    ├── [0]
+    │   ├── name: build
+    │   ├── status: Passed
+    │   ├── counter: 10
+    │   └── stages
+    │       ├── compile
+    │       └── test
+    └── [1]
+        ├── name: deploy
+        ├── status: Failed
+        ├── counter: 3
+        └── stages: []
+`, strReader.String())
+	})
+
+	t.Run("should label list items by field with ascii connectors", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, false)
+		render.Tree = true
+		render.TreeOptions = renderer.TreeOptions{LabelField: "name", ASCII: true}
+
+		err := render.Render(group["pipelines"])
+		require.NoError(t, err)
+		assert.Equal(t, ".\n"+
+			"|-- build\n"+
+			"|   |-- status: Passed\n"+
+			"|   |-- counter: 10\n"+
+			"|   `-- stages\n"+
+			"|       |-- compile\n"+
+			"|       `-- test\n"+
+			"`-- deploy\n"+
+			"    |-- status: Failed\n"+
+			"    |-- counter: 3\n"+
+			"    `-- stages: []\n", strReader.String())
+	})
+
+	t.Run("should summarise the levels beyond max depth", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, false)
+		render.Tree = true
+		render.TreeOptions = renderer.TreeOptions{MaxDepth: 2, LabelField: "name"}
+
+		err := render.Render(group)
+		require.NoError(t, err)
+		assert.Equal(t, `.
+├── group: sample
+└── pipelines
+    ├── build {3 keys …}
+    └── deploy {3 keys …}
+`, strReader.String())
+	})
+
+	t.Run("should render scalars as is", func(t *testing.T) {
+		strReader := new(bytes.Buffer)
+
+		render := renderer.GetRenderer(strReader, logrus.New(), true, false, false, false, false)
+		render.Tree = true
+
+		err := render.Render("sample")
+		require.NoError(t, err)
+		assert.Equal(t, "sample\n", strReader.String())
+	})
+}